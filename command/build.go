@@ -6,10 +6,12 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"math"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,6 +21,7 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer/internal/hcp/registry"
 	"github.com/hashicorp/packer/packer"
+	"github.com/hashicorp/packer/version"
 	"golang.org/x/sync/semaphore"
 
 	"github.com/hako/durafmt"
@@ -58,6 +61,10 @@ func (c *BuildCommand) ParseArgs(args []string) (*BuildArgs, int) {
 		cfg.ParallelBuilds = math.MaxInt64
 	}
 
+	if cfg.ParallelPostProcessors < 1 {
+		cfg.ParallelPostProcessors = math.MaxInt64
+	}
+
 	args = flags.Args()
 	if len(args) != 1 {
 		flags.Usage()
@@ -102,8 +109,14 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		return ret
 	}
 
+	// The metadata of the environment is gathered whether HCP is enabled or
+	// not, so that templates and post-processors can record it.
+	envMetadata := &registry.MetadataStore{}
+	envMetadata.Gather(GetCleanedBuildArgs(cla))
+
 	diags = packerStarter.Initialize(packer.InitializeOptions{
 		UseSequential: cla.UseSequential,
+		EnvMetadata:   envMetadata.BuildMetadata(),
 	})
 
 	if packer.PackerUseProto {
@@ -183,7 +196,19 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		packer.UiColorBlue,
 	}
 	buildUis := make(map[*packer.CoreBuild]packersdk.Ui)
+	var reports []*packer.BuildReport
 	for i := range builds {
+		if cla.Debug {
+			builds[i].SetPostProcessorParallelism(1)
+		} else {
+			builds[i].SetPostProcessorParallelism(cla.ParallelPostProcessors)
+		}
+		if cla.ReportPath != "" {
+			report := packer.NewBuildReport(builds[i])
+			builds[i].SetReport(report)
+			reports = append(reports, report)
+		}
+
 		ui := c.Ui
 		if cla.Color {
 			// Only set up UI colors if -machine-readable isn't set.
@@ -373,6 +398,15 @@ Check that you are using an HCP Ready integration before trying again:
 	fmtBuildCommandDuration := durafmt.Parse(buildCommandDuration).LimitFirstN(2)
 	c.Ui.Say(fmt.Sprintf("\n==> Wait completed after %s", fmtBuildCommandDuration))
 
+	if cla.ReportPath != "" {
+		if err := writeBuildReport(cla.ReportPath, buildCommandStart, buildCommandEnd, reports); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write build report: %s", err))
+			ret = 1
+		} else {
+			c.Ui.Say(fmt.Sprintf("Build report written to %s", cla.ReportPath))
+		}
+	}
+
 	if err := buildCtx.Err(); err != nil {
 		c.Ui.Say("Cleanly cancelled builds after being interrupted.")
 		return 1
@@ -453,6 +487,35 @@ Check that you are using an HCP Ready integration before trying again:
 	return ret
 }
 
+// buildCommandReport is the document written by `packer build -report`.
+type buildCommandReport struct {
+	PackerVersion   string                `json:"packer_version"`
+	StartTime       time.Time             `json:"start_time"`
+	EndTime         time.Time             `json:"end_time"`
+	DurationSeconds float64               `json:"duration_seconds"`
+	Builds          []*packer.BuildReport `json:"builds"`
+}
+
+func writeBuildReport(path string, start, end time.Time, builds []*packer.BuildReport) error {
+	if builds == nil {
+		builds = []*packer.BuildReport{}
+	}
+	report := buildCommandReport{
+		PackerVersion:   version.FormattedVersion(),
+		StartTime:       start.UTC(),
+		EndTime:         end.UTC(),
+		DurationSeconds: end.Sub(start).Seconds(),
+		Builds:          builds,
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
 func (*BuildCommand) Help() string {
 	helpText := `
 Usage: packer build [options] TEMPLATE
@@ -470,6 +533,7 @@ Options:
   -machine-readable             Produce machine-readable output.
   -on-error=[cleanup|abort|ask|run-cleanup-provisioner] If the build fails do: clean up (default), abort, ask, or run-cleanup-provisioner.
   -parallel-builds=1            Number of builds to run in parallel. 1 disables parallelization. 0 means no limit (Default: 0)
+  -parallel-post-processors=1   Number of post-processor sequences of a build to run in parallel. 0 means no limit (Default: 1)
   -timestamp-ui                 Enable prefixing of each ui output with an RFC3339 timestamp.
   -var 'key=value'              Variable for templates, can be used multiple times.
   -var-file=path                JSON or HCL2 file containing user variables, can be used multiple times.
@@ -477,6 +541,7 @@ Options:
   -ignore-prerelease-plugins    Disable the loading of prerelease plugin binaries (x.y.z-dev).
   -use-sequential-evaluation    Fallback to using a sequential approach for local/datasource evaluation.
   -skip-enforcement             Skip injection of HCP Packer enforced provisioners.
+  -report=path.json             Write a JSON report of the builds, their steps and artifacts to this path.
 `
 
 	return strings.TrimSpace(helpText)
@@ -492,16 +557,18 @@ func (*BuildCommand) AutocompleteArgs() complete.Predictor {
 
 func (*BuildCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-color":            complete.PredictNothing,
-		"-debug":            complete.PredictNothing,
-		"-except":           complete.PredictNothing,
-		"-only":             complete.PredictNothing,
-		"-force":            complete.PredictNothing,
-		"-machine-readable": complete.PredictNothing,
-		"-on-error":         complete.PredictNothing,
-		"-parallel":         complete.PredictNothing,
-		"-timestamp-ui":     complete.PredictNothing,
-		"-var":              complete.PredictNothing,
-		"-var-file":         complete.PredictNothing,
+		"-color":                    complete.PredictNothing,
+		"-debug":                    complete.PredictNothing,
+		"-except":                   complete.PredictNothing,
+		"-only":                     complete.PredictNothing,
+		"-force":                    complete.PredictNothing,
+		"-machine-readable":         complete.PredictNothing,
+		"-on-error":                 complete.PredictNothing,
+		"-parallel":                 complete.PredictNothing,
+		"-parallel-post-processors": complete.PredictNothing,
+		"-report":                   complete.PredictNothing,
+		"-timestamp-ui":             complete.PredictNothing,
+		"-var":                      complete.PredictNothing,
+		"-var-file":                 complete.PredictNothing,
 	}
 }
@@ -4,6 +4,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -677,6 +678,66 @@ func TestBuildOnlyFileMultipleFlags(t *testing.T) {
 	}
 }
 
+func TestBuildReport(t *testing.T) {
+	c := &BuildCommand{
+		Meta: TestMetaFile(t),
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	args := []string{
+		"-parallel-builds=1",
+		"-only=file.vanilla",
+		"-report=" + reportPath,
+		filepath.Join(testFixture("build-only"), "template.pkr.hcl"),
+	}
+
+	defer cleanup()
+
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %s", err)
+	}
+
+	var report buildCommandReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("failed to decode report: %s", err)
+	}
+
+	if len(report.Builds) != 1 {
+		t.Fatalf("expected 1 build in report, got %d", len(report.Builds))
+	}
+	build := report.Builds[0]
+	if build.Name != "file.vanilla" {
+		t.Errorf("expected build name %q, got %q", "file.vanilla", build.Name)
+	}
+	if build.Error != "" {
+		t.Errorf("expected no build error, got %q", build.Error)
+	}
+	if build.EndTime.Before(build.StartTime) {
+		t.Errorf("build ended before it started: %s < %s", build.EndTime, build.StartTime)
+	}
+
+	var ppNames []string
+	for _, pp := range build.PostProcessors {
+		if pp.Type != "shell-local" {
+			t.Errorf("unexpected post-processor type %q", pp.Type)
+		}
+		ppNames = append(ppNames, pp.Name)
+	}
+	expectedPPs := []string{"apple", "peach", "pear", "banana", "tomato"}
+	if diff := cmp.Diff(expectedPPs, ppNames); diff != "" {
+		t.Errorf("unexpected post-processors in report: %s", diff)
+	}
+
+	if len(build.Artifacts) == 0 {
+		t.Fatal("expected artifacts in report")
+	}
+}
+
 func TestBuildProvisionAndPosProcessWithBuildVariablesSharing(t *testing.T) {
 	c := &BuildCommand{
 		Meta: TestMetaFile(t),
@@ -1089,55 +1150,81 @@ func TestBuildCommand_ParseArgs(t *testing.T) {
 		{fields{defaultMeta},
 			args{[]string{"file.json"}},
 			&BuildArgs{
-				MetaArgs:       MetaArgs{Path: "file.json"},
-				ParallelBuilds: math.MaxInt64,
-				Color:          true,
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         math.MaxInt64,
+				ParallelPostProcessors: 1,
+				Color:                  true,
 			},
 			0,
 		},
 		{fields{defaultMeta},
 			args{[]string{"-parallel-builds=10", "file.json"}},
 			&BuildArgs{
-				MetaArgs:       MetaArgs{Path: "file.json"},
-				ParallelBuilds: 10,
-				Color:          true,
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         10,
+				ParallelPostProcessors: 1,
+				Color:                  true,
 			},
 			0,
 		},
 		{fields{defaultMeta},
 			args{[]string{"-parallel-builds=1", "file.json"}},
 			&BuildArgs{
-				MetaArgs:       MetaArgs{Path: "file.json"},
-				ParallelBuilds: 1,
-				Color:          true,
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         1,
+				ParallelPostProcessors: 1,
+				Color:                  true,
 			},
 			0,
 		},
 		{fields{defaultMeta},
 			args{[]string{"-parallel-builds=5", "file.json"}},
 			&BuildArgs{
-				MetaArgs:       MetaArgs{Path: "file.json"},
-				ParallelBuilds: 5,
-				Color:          true,
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         5,
+				ParallelPostProcessors: 1,
+				Color:                  true,
 			},
 			0,
 		},
 		{fields{defaultMeta},
 			args{[]string{"-parallel-builds=1", "-parallel-builds=5", "otherfile.json"}},
 			&BuildArgs{
-				MetaArgs:       MetaArgs{Path: "otherfile.json"},
-				ParallelBuilds: 5,
-				Color:          true,
+				MetaArgs:               MetaArgs{Path: "otherfile.json"},
+				ParallelBuilds:         5,
+				ParallelPostProcessors: 1,
+				Color:                  true,
 			},
 			0,
 		},
 		{fields{defaultMeta},
 			args{[]string{"-skip-enforcement", "file.json"}},
 			&BuildArgs{
-				MetaArgs:        MetaArgs{Path: "file.json"},
-				ParallelBuilds:  math.MaxInt64,
-				Color:           true,
-				SkipEnforcement: true,
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         math.MaxInt64,
+				ParallelPostProcessors: 1,
+				Color:                  true,
+				SkipEnforcement:        true,
+			},
+			0,
+		},
+		{fields{defaultMeta},
+			args{[]string{"-parallel-post-processors=0", "file.json"}},
+			&BuildArgs{
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         math.MaxInt64,
+				ParallelPostProcessors: math.MaxInt64,
+				Color:                  true,
+			},
+			0,
+		},
+		{fields{defaultMeta},
+			args{[]string{"-parallel-post-processors=4", "file.json"}},
+			&BuildArgs{
+				MetaArgs:               MetaArgs{Path: "file.json"},
+				ParallelBuilds:         math.MaxInt64,
+				ParallelPostProcessors: 4,
+				Color:                  true,
 			},
 			0,
 		},
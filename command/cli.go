@@ -92,6 +92,7 @@ func (ba *BuildArgs) AddFlagSets(flags *flag.FlagSet) {
 	flags.BoolVar(&ba.MachineReadable, "machine-readable", false, "")
 
 	flags.Int64Var(&ba.ParallelBuilds, "parallel-builds", 0, "")
+	flags.Int64Var(&ba.ParallelPostProcessors, "parallel-post-processors", 1, "")
 
 	flagOnError := enumflag.New(&ba.OnError, "cleanup", "abort", "ask", "run-cleanup-provisioner")
 	flags.Var(flagOnError, "on-error", "")
@@ -103,6 +104,8 @@ func (ba *BuildArgs) AddFlagSets(flags *flag.FlagSet) {
 
 	flags.BoolVar(&ba.SkipEnforcement, "skip-enforcement", false, "Skip injection of HCP Packer enforced provisioners. Requires admin privileges.")
 
+	flags.StringVar(&ba.ReportPath, "report", "", "Write a JSON report of the builds to this path.")
+
 	ba.MetaArgs.AddFlagSets(flags)
 }
 
@@ -136,9 +139,11 @@ type BuildArgs struct {
 	Debug, Force                        bool
 	Color, TimestampUi, MachineReadable bool
 	ParallelBuilds                      int64
+	ParallelPostProcessors              int64
 	OnError                             string
 	ReleaseOnly                         bool
 	SkipEnforcement                     bool
+	ReportPath                          string
 }
 
 func (ia *InitArgs) AddFlagSets(flags *flag.FlagSet) {
@@ -171,6 +176,8 @@ type ConsoleArgs struct {
 
 func (fa *FixArgs) AddFlagSets(flags *flag.FlagSet) {
 	flags.BoolVar(&fa.Validate, "validate", true, "")
+	flags.BoolVar(&fa.Diff, "diff", false, "display the diff of the fixes of HCL2 files")
+	flags.BoolVar(&fa.Write, "write", false, "overwrite HCL2 files with their fixed version")
 
 	fa.MetaArgs.AddFlagSets(flags)
 }
@@ -179,6 +186,9 @@ func (fa *FixArgs) AddFlagSets(flags *flag.FlagSet) {
 type FixArgs struct {
 	MetaArgs
 	Validate bool
+	// Diff and Write only apply to HCL2 templates, JSON templates are always
+	// printed fixed.
+	Diff, Write bool
 }
 
 func (va *ValidateArgs) AddFlagSets(flags *flag.FlagSet) {
@@ -200,6 +210,7 @@ type ValidateArgs struct {
 }
 
 func (va *InspectArgs) AddFlagSets(flags *flag.FlagSet) {
+	flags.Var(enumflag.New(&va.Format, "json"), "format", "")
 	flags.BoolVar(&va.MetaArgs.UseSequential, "use-sequential-evaluation", false, "Fallback to using a sequential approach for local/datasource evaluation.")
 	va.MetaArgs.AddFlagSets(flags)
 }
@@ -207,6 +218,22 @@ func (va *InspectArgs) AddFlagSets(flags *flag.FlagSet) {
 // InspectArgs represents a parsed cli line for a `packer inspect`
 type InspectArgs struct {
 	MetaArgs
+	Format string
+}
+
+func (va *GraphArgs) AddFlagSets(flags *flag.FlagSet) {
+	flagFormat := enumflag.New(&va.Format, "dot", "mermaid", "json")
+	flags.Var(flagFormat, "format", "")
+	flags.BoolVar(&va.IncludeVariables, "include-variables", false, "Add input variables and the references to them to the graph.")
+	flags.BoolVar(&va.MetaArgs.UseSequential, "use-sequential-evaluation", false, "Fallback to using a sequential approach for local/datasource evaluation.")
+	va.MetaArgs.AddFlagSets(flags)
+}
+
+// GraphArgs represents a parsed cli line for a `packer graph`
+type GraphArgs struct {
+	MetaArgs
+	Format           string
+	IncludeVariables bool
 }
 
 func (va *HCL2UpgradeArgs) AddFlagSets(flags *flag.FlagSet) {
@@ -21,11 +21,13 @@ import (
 	hcppackerversiondatasource "github.com/hashicorp/packer/datasource/hcp-packer-version"
 	httpdatasource "github.com/hashicorp/packer/datasource/http"
 	nulldatasource "github.com/hashicorp/packer/datasource/null"
+	registryartifactdatasource "github.com/hashicorp/packer/datasource/registry-artifact"
 	artificepostprocessor "github.com/hashicorp/packer/post-processor/artifice"
 	checksumpostprocessor "github.com/hashicorp/packer/post-processor/checksum"
 	compresspostprocessor "github.com/hashicorp/packer/post-processor/compress"
 	manifestpostprocessor "github.com/hashicorp/packer/post-processor/manifest"
 	shelllocalpostprocessor "github.com/hashicorp/packer/post-processor/shell-local"
+	signpostprocessor "github.com/hashicorp/packer/post-processor/sign"
 	breakpointprovisioner "github.com/hashicorp/packer/provisioner/breakpoint"
 	fileprovisioner "github.com/hashicorp/packer/provisioner/file"
 	hcpsbomprovisioner "github.com/hashicorp/packer/provisioner/hcp-sbom"
@@ -64,6 +66,7 @@ var PostProcessors = map[string]packersdk.PostProcessor{
 	"compress":    new(compresspostprocessor.PostProcessor),
 	"manifest":    new(manifestpostprocessor.PostProcessor),
 	"shell-local": new(shelllocalpostprocessor.PostProcessor),
+	"sign":        new(signpostprocessor.PostProcessor),
 }
 
 var Datasources = map[string]packersdk.Datasource{
@@ -73,6 +76,7 @@ var Datasources = map[string]packersdk.Datasource{
 	"hcp-packer-version":   new(hcppackerversiondatasource.Datasource),
 	"http":                 new(httpdatasource.Datasource),
 	"null":                 new(nulldatasource.Datasource),
+	"registry-artifact":    new(registryartifactdatasource.Datasource),
 }
 
 var pluginRegexp = regexp.MustCompile("packer-(builder|post-processor|provisioner|datasource)-(.+)")
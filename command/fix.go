@@ -14,6 +14,7 @@ import (
 
 	"github.com/hashicorp/packer-plugin-sdk/template"
 	"github.com/hashicorp/packer/fix"
+	"github.com/hashicorp/packer/hcl2template"
 
 	"github.com/posener/complete"
 )
@@ -54,8 +55,7 @@ func (c *FixCommand) ParseArgs(args []string) (*FixArgs, int) {
 
 func (c *FixCommand) RunContext(ctx context.Context, cla *FixArgs) int {
 	if hcl2, _ := isHCLLoaded(cla.Path); hcl2 {
-		c.Ui.Error("packer fix only works with JSON files for now.")
-		return 1
+		return c.fixHCL2(cla)
 	}
 	// Read the file for decoding
 	tplF, err := os.Open(cla.Path)
@@ -136,12 +136,30 @@ func (c *FixCommand) RunContext(ctx context.Context, cla *FixArgs) int {
 	return 0
 }
 
+func (c *FixCommand) fixHCL2(cla *FixArgs) int {
+	fixer := hcl2template.HCL2Fixer{
+		ShowDiff: cla.Diff,
+		Write:    cla.Write,
+		Output:   os.Stdout,
+	}
+
+	_, diags := fixer.Fix([]string{cla.Path})
+	return writeDiags(c.Ui, nil, diags)
+}
+
 func (*FixCommand) Help() string {
 	helpText := `
 Usage: packer fix [options] TEMPLATE
 
-  Reads the JSON template and attempts to fix known backwards
-  incompatibilities. The fixed template will be outputted to standard out.
+  Reads the template and attempts to fix known backwards
+  incompatibilities.
+
+  A JSON template is fixed and outputted to standard out.
+
+  For HCL2 templates, TEMPLATE can be a file or a directory. The names of the
+  HCL2 configuration files that need fixing are outputted, and the files are
+  rewritten in place when -write is set. Comments are preserved. Fixers
+  marked with a * also run on HCL2 templates.
 
   If the template cannot be fixed due to an error, the command will exit
   with a non-zero exit status. Error messages will appear on standard error.
@@ -150,15 +168,25 @@ Fixes that are run (in order):
 
 `
 
+	hcl2Fixers := map[string]bool{}
+	for _, name := range fix.HCL2FixerOrder() {
+		hcl2Fixers[name] = true
+	}
 	for _, name := range fix.FixerOrder {
+		marker := " "
+		if hcl2Fixers[name] {
+			marker = "*"
+		}
 		helpText += fmt.Sprintf(
-			"  %-27s%s\n", name, fix.Fixers[name].Synopsis())
+			"  %s %-27s%s\n", marker, name, fix.Fixers[name].Synopsis())
 	}
 
 	helpText += `
 Options:
 
-  -validate=true      If true (default), validates the fixed template.
+  -validate=true      If true (default), validates the fixed JSON template.
+  -diff               Display the diffs of the fixes of HCL2 files.
+  -write              Overwrite HCL2 files with their fixed version.
 `
 
 	return strings.TrimSpace(helpText)
@@ -175,5 +203,7 @@ func (c *FixCommand) AutocompleteArgs() complete.Predictor {
 func (c *FixCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
 		"-validate": complete.PredictNothing,
+		"-diff":     complete.PredictNothing,
+		"-write":    complete.PredictNothing,
 	}
 }
@@ -4,6 +4,7 @@
 package command
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -64,3 +65,32 @@ func TestFix_invalidTemplateDisableValidation(t *testing.T) {
 		fatalCommand(t, c.Meta)
 	}
 }
+
+func TestFix_hcl2(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template.pkr.hcl")
+	if err := os.WriteFile(template, []byte(testFixtureContent("fix-hcl2", "template.pkr.hcl")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &FixCommand{
+		Meta: testMeta(t),
+	}
+	if code := c.Run([]string{dir}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+	b, err := os.ReadFile(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testFixtureContent("fix-hcl2", "template.pkr.hcl"), string(b), "files are only written with -write")
+
+	if code := c.Run([]string{"-write", dir}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+	b, err = os.ReadFile(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testFixtureContent("fix-hcl2", "expected.txt"), string(b))
+}
@@ -0,0 +1,131 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/packer/packer"
+	"github.com/posener/complete"
+)
+
+type GraphCommand struct {
+	Meta
+}
+
+func (c *GraphCommand) Run(args []string) int {
+	ctx := context.Background()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *GraphCommand) ParseArgs(args []string) (*GraphArgs, int) {
+	cfg := GraphArgs{Format: "dot"}
+	flags := c.Meta.FlagSet("graph")
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Path = args[0]
+	return &cfg, 0
+}
+
+func (c *GraphCommand) RunContext(ctx context.Context, cla *GraphArgs) int {
+	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
+	if ret != 0 {
+		return ret
+	}
+
+	grapher, ok := packerStarter.(packer.ConfigGrapher)
+	if !ok {
+		c.Ui.Error("The graph command only supports HCL2 templates")
+		return 1
+	}
+
+	// Datasources are not executed, and init diags are ignored to allow
+	// unknown variables to be used, like for inspect.
+	_ = packerStarter.Initialize(packer.InitializeOptions{
+		SkipDatasourcesExecution: true,
+		UseSequential:            cla.UseSequential,
+	})
+
+	graph, diags := grapher.ConfigGraph(packer.ConfigGraphOptions{
+		IncludeVariables: cla.IncludeVariables,
+	})
+	if ret := writeDiags(c.Ui, nil, diags); ret != 0 {
+		return ret
+	}
+
+	switch cla.Format {
+	case "mermaid":
+		c.Ui.Message(strings.TrimSuffix(graph.Mermaid(), "\n"))
+	case "json":
+		out, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to encode the graph: %s", err))
+			return 1
+		}
+		c.Ui.Message(string(out))
+	default:
+		c.Ui.Message(strings.TrimSuffix(graph.DOT(), "\n"))
+	}
+
+	return 0
+}
+
+func (*GraphCommand) Help() string {
+	helpText := `
+Usage: packer graph [options] TEMPLATE
+
+  Outputs the dependency graph of a template: its locals and datasources,
+  and its builds with their sources, provisioners and post-processors,
+  linked to the blocks they reference. Datasources are not executed.
+
+  Only HCL2 templates are supported.
+
+  Ex: packer graph . | dot -Tsvg > graph.svg
+
+Options:
+
+  -format=dot                   Output format: dot (default), mermaid or json.
+  -include-variables            Add input variables and the references to them to the graph.
+  -var 'key=value'              Variable for templates, can be used multiple times.
+  -var-file=path                JSON or HCL2 file containing user variables, can be used multiple times.
+  -use-sequential-evaluation    Fallback to using a sequential approach for local/datasource evaluation.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (*GraphCommand) Synopsis() string {
+	return "output the dependency graph of a template"
+}
+
+func (*GraphCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (*GraphCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-format":            complete.PredictSet("dot", "mermaid", "json"),
+		"-include-variables": complete.PredictNothing,
+		"-var":               complete.PredictNothing,
+		"-var-file":          complete.PredictNothing,
+	}
+}
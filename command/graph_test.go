@@ -0,0 +1,147 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/packer/packer"
+)
+
+func TestGraphCommand_dot(t *testing.T) {
+	c := &GraphCommand{
+		Meta: TestMetaFile(t),
+	}
+	if code := c.Run([]string{testFixture("graph")}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	expected := `digraph {
+	compound = "true"
+	newrank = "true"
+	"local.target" [label="local.target", shape="note"]
+	"data.null.base" [label="data.null.base", shape="cylinder"]
+	"source.file.example" [label="source.file.example", shape="box"]
+	"build.example" [label="build \"example\"", shape="box3d"]
+	"build.example.provisioner[0]" [label="provisioner \"shell-local\"", shape="box"]
+	"build.example.provisioner[1]" [label="provisioner \"shell-local\"", shape="box"]
+	"build.example.post-processor[0][0]" [label="post-processor \"manifest\"", shape="box"]
+	"local.target" -> "data.null.base" [label="depends_on", style="dashed"]
+	"source.file.example" -> "local.target" [label="depends_on", style="dashed"]
+	"build.example" -> "source.file.example" [label="contains", style="solid"]
+	"build.example" -> "build.example.provisioner[0]" [label="contains", style="solid"]
+	"build.example" -> "build.example.provisioner[1]" [label="contains", style="solid"]
+	"build.example.provisioner[0]" -> "build.example.provisioner[1]" [label="next", style="solid"]
+	"build.example.provisioner[1]" -> "local.target" [label="depends_on", style="dashed"]
+	"build.example" -> "build.example.post-processor[0][0]" [label="contains", style="solid"]
+	"build.example.post-processor[0][0]" -> "local.target" [label="depends_on", style="dashed"]
+}
+`
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	if diff := cmp.Diff(expected, out); diff != "" {
+		t.Errorf("unexpected output: %s", diff)
+	}
+}
+
+func TestGraphCommand_jsonWithVariables(t *testing.T) {
+	c := &GraphCommand{
+		Meta: TestMetaFile(t),
+	}
+	if code := c.Run([]string{"-format=json", "-include-variables", testFixture("graph")}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	var graph packer.ConfigGraph
+	if err := json.Unmarshal([]byte(out), &graph); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, out)
+	}
+
+	if graph.Nodes[0] != (packer.ConfigGraphNode{ID: "var.name", Type: "variable", Label: "var.name"}) {
+		t.Errorf("expected the variable to be the first node, got %v", graph.Nodes[0])
+	}
+	for _, e := range []packer.ConfigGraphEdge{
+		{From: "data.null.base", To: "var.name", Kind: packer.ConfigGraphDependsOn},
+		{From: "build.example.provisioner[0]", To: "var.name", Kind: packer.ConfigGraphDependsOn},
+	} {
+		found := false
+		for _, edge := range graph.Edges {
+			found = found || edge == e
+		}
+		if !found {
+			t.Errorf("missing edge %v in %v", e, graph.Edges)
+		}
+	}
+}
+
+func TestGraphCommand_mermaid(t *testing.T) {
+	c := &GraphCommand{
+		Meta: TestMetaFile(t),
+	}
+	if code := c.Run([]string{"-format=mermaid", testFixture("graph")}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	for _, line := range []string{
+		"flowchart LR\n",
+		`    n3["build #quot;example#quot;"]` + "\n",
+		"    n0 -.->|depends_on| n1\n",
+		"    n3 -->|contains| n2\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected %q in output:\n%s", line, out)
+		}
+	}
+}
+
+func TestGraphCommand_invalidArgs(t *testing.T) {
+	tests := map[string][]string{
+		"unknown format": {"-format=svg", testFixture("graph")},
+		"json template":  {filepath.Join(testFixture("validate"), "build.json")},
+		"no template":    {},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &GraphCommand{
+				Meta: TestMetaFile(t),
+			}
+			if code := c.Run(args); code != 1 {
+				t.Errorf("expected exit code 1, got %d", code)
+			}
+		})
+	}
+}
+
+func TestGraphCommand_communicator(t *testing.T) {
+	c := &GraphCommand{
+		Meta: TestMetaFile(t),
+	}
+	if code := c.Run([]string{"-format=json", "-include-variables", testFixture("graph-communicator")}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	var graph packer.ConfigGraph
+	if err := json.Unmarshal([]byte(out), &graph); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, out)
+	}
+
+	for _, e := range []packer.ConfigGraphEdge{
+		{From: "source.null.web", To: "communicator.ssh.bastion", Kind: packer.ConfigGraphDependsOn},
+		{From: "communicator.ssh.bastion", To: "var.bastion_host", Kind: packer.ConfigGraphDependsOn},
+	} {
+		found := false
+		for _, edge := range graph.Edges {
+			found = found || edge == e
+		}
+		if !found {
+			t.Errorf("missing edge %v in %v", e, graph.Edges)
+		}
+	}
+}
@@ -11,14 +11,10 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/hashicorp/packer/packer/plugin-getter/release"
-
 	gversion "github.com/hashicorp/go-version"
 	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
 	"github.com/hashicorp/packer/packer"
 	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
-	"github.com/hashicorp/packer/packer/plugin-getter/github"
-	"github.com/hashicorp/packer/version"
 	"github.com/posener/complete"
 )
 
@@ -97,22 +93,10 @@ for more info.`)
 
 	log.Printf("[TRACE] init: %#v", opts)
 
-	// the ordering of the getters is important here, place the getter on top which you want to try first
-	getters := []plugingetter.Getter{
-		&release.Getter{
-			Name: "releases.hashicorp.com",
-		},
-		&github.Getter{
-			// In the past some terraform plugins downloads were blocked from a
-			// specific aws region by s3. Changing the user agent unblocked the
-			// downloads so having one user agent per version will help mitigate
-			// that a little more. Especially in the case someone forks this
-			// code to make it more aggressive or something.
-			// TODO: allow to set this from the config file or an environment
-			// variable.
-			UserAgent: "packer-getter-github-" + version.String(),
-			Name:      "github.com",
-		},
+	getters, err := c.Meta.pluginGetters()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
 	}
 
 	ui := &packer.ColoredUi{
@@ -120,7 +104,49 @@ for more info.`)
 		Ui:    c.Ui,
 	}
 
+	lockPath := plugingetter.LockFilePath(cla.Path)
+	lock, err := plugingetter.ReadLockFile(lockPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read lock file %q: %s", lockPath, err))
+		return 1
+	}
+	lockExists := lock.Exists()
+
 	for _, pluginRequirement := range reqs {
+		requiredConstraints := pluginRequirement.VersionConstraints
+
+		// Unless upgrading, stick to the version recorded in the lock file.
+		locked, isLocked := lock.Plugins[pluginRequirement.Identifier.String()]
+		platform := opts.BinaryInstallationOptions.Platform()
+		needsVerification := false
+		if isLocked && !cla.Upgrade {
+			lockedVersion, err := gversion.NewVersion(locked.Version)
+			if err != nil || !requiredConstraints.Check(lockedVersion) {
+				c.Ui.Error(fmt.Sprintf("The %q plugin is locked to version %s in %q, which does not satisfy %q. "+
+					"Run packer init -upgrade to select a new version.",
+					pluginRequirement.Identifier, locked.Version, lockPath, requiredConstraints))
+				ret = 1
+				continue
+			}
+			pluginRequirement.VersionConstraints, _ = locked.VersionConstraints()
+
+			// Without a binary hash for this platform, the plugin is
+			// reinstalled from the release, once its checksum file was
+			// checked against the lock, and its binary hash recorded. The
+			// archive itself is verified against the checksum file when
+			// installing.
+			_, hasHash := locked.Hashes[platform]
+			needsVerification = !hasHash
+			if needsVerification {
+				if err := c.verifyReleaseChecksum(pluginRequirement, locked, lockedVersion, opts, getters); err != nil {
+					c.Ui.Error(fmt.Sprintf("The %q plugin cannot be verified against the lock file %q: %s",
+						pluginRequirement.Identifier, lockPath, err))
+					ret = 1
+					continue
+				}
+			}
+		}
+
 		// Get installed plugins that match requirement
 
 		installs, err := pluginRequirement.ListInstallations(opts)
@@ -129,12 +155,9 @@ for more info.`)
 			return 1
 		}
 
-		if len(installs) > 0 {
-			if !cla.Force && !cla.Upgrade {
-				continue
-			}
-
-			if cla.Force && !cla.Upgrade {
+		force := cla.Force || needsVerification
+		if len(installs) == 0 || force || cla.Upgrade {
+			if len(installs) > 0 && force && !cla.Upgrade {
 				// Only place another constaint to the latest release
 				// binary, if any, otherwise this is essentially the same
 				// as an upgrade
@@ -150,27 +173,95 @@ for more info.`)
 					pluginRequirement.VersionConstraints, _ = gversion.NewConstraint(fmt.Sprintf("=%s", installVersion))
 				}
 			}
+
+			newInstall, err := pluginRequirement.InstallLatest(plugingetter.InstallOptions{
+				PluginDirectory:           opts.PluginDirectory,
+				BinaryInstallationOptions: opts.BinaryInstallationOptions,
+				Getters:                   getters,
+				Force:                     force,
+			})
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Failed getting the %q plugin:", pluginRequirement.Identifier))
+				c.Ui.Error(err.Error())
+				ret = 1
+				continue
+			}
+			if newInstall != nil {
+				msg := fmt.Sprintf("Installed plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
+				ui.Say(msg)
+			}
+
+			installs, err = pluginRequirement.ListInstallations(opts)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
 		}
 
-		newInstall, err := pluginRequirement.InstallLatest(plugingetter.InstallOptions{
-			PluginDirectory:           opts.PluginDirectory,
-			BinaryInstallationOptions: opts.BinaryInstallationOptions,
-			Getters:                   getters,
-			Force:                     cla.Force,
-		})
-		if err != nil {
-			c.Ui.Error(fmt.Sprintf("Failed getting the %q plugin:", pluginRequirement.Identifier))
-			c.Ui.Error(err.Error())
+		if len(installs) == 0 {
+			continue
+		}
+		install := installs[len(installs)-1]
+
+		if isLocked && !cla.Upgrade && !needsVerification {
+			if err := locked.Verify(install, opts.BinaryInstallationOptions); err != nil {
+				c.Ui.Error(fmt.Sprintf("The %q plugin does not match the lock file: %s", pluginRequirement.Identifier, err))
+				ret = 1
+				continue
+			}
+		}
+
+		// Record the release checksums of every platform, so that the lock
+		// can be verified on platforms other than this one.
+		var zipHashes map[string]string
+		if !isLocked || cla.Upgrade || len(locked.ZipHashes) == 0 ||
+			locked.Version != strings.TrimPrefix(install.Version, "v") {
+			zipHashes = c.releaseChecksums(pluginRequirement, install, opts, getters)
+		}
+
+		pluginRequirement.VersionConstraints = requiredConstraints
+		if err := lock.Record(pluginRequirement, install, opts.BinaryInstallationOptions, zipHashes); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to lock the %q plugin: %s", pluginRequirement.Identifier, err))
 			ret = 1
 		}
-		if newInstall != nil {
-			msg := fmt.Sprintf("Installed plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
-			ui.Say(msg)
+	}
+
+	lock.Retain(reqs)
+	if lock.Exists() || lockExists {
+		if err := lock.Write(lockPath); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write lock file %q: %s", lockPath, err))
+			return 1
 		}
 	}
+
 	return ret
 }
 
+// verifyReleaseChecksum checks that the checksum file of the locked release
+// of a plugin has, for the current platform, the archive checksum recorded in
+// the lock.
+func (c *InitCommand) verifyReleaseChecksum(pr *plugingetter.Requirement, locked *plugingetter.LockedPlugin, version *gversion.Version, opts plugingetter.ListInstallationsOptions, getters []plugingetter.Getter) error {
+	platform := opts.BinaryInstallationOptions.Platform()
+	expected, ok := locked.ZipHashes[platform]
+	if !ok {
+		return fmt.Errorf("no checksum is recorded for %s. Run packer init -upgrade to "+
+			"record the checksums of all platforms", platform)
+	}
+	hashes, err := pr.ReleaseChecksums(plugingetter.InstallOptions{
+		PluginDirectory:           opts.PluginDirectory,
+		BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		Getters:                   getters,
+	}, version)
+	if err != nil {
+		return err
+	}
+	if hashes[platform] != expected {
+		return fmt.Errorf("the checksum of the %s release archive of version %s is %q, "+
+			"the lock file expects %q", platform, version, hashes[platform], expected)
+	}
+	return nil
+}
+
 func (*InitCommand) Help() string {
 	helpText := `
 Usage: packer init [options] TEMPLATE
@@ -178,6 +269,11 @@ Usage: packer init [options] TEMPLATE
   Install all the missing plugins required in a Packer config. Note that Packer
   does not have a state.
 
+  The exact version and checksum of every installed plugin are recorded in a
+  .packer.lock.hcl file next to the config. Once present, packer init installs
+  the locked versions, and packer build and validate refuse plugins that do
+  not match it. Commit this file so that everyone uses the same plugins.
+
   This is the first command that should be executed when working with a new
   or existing template.
 
@@ -189,7 +285,8 @@ Options:
                                installed plugins to the latest available
                                version, if there is a new higher one. Note that
                                this still takes into consideration the version
-                               constraint of the config.
+                               constraint of the config. The versions recorded
+                               in the .packer.lock.hcl lock file are updated.
   -force                       Forces reinstallation of plugins, even if already
                                installed.
 `
@@ -54,7 +54,8 @@ func (c *InspectCommand) RunContext(ctx context.Context, cla *InspectArgs) int {
 	})
 
 	return packerStarter.InspectConfig(packer.InspectConfigOptions{
-		Ui: c.Ui,
+		Ui:     c.Ui,
+		Format: cla.Format,
 	})
 }
 
@@ -68,6 +69,7 @@ Usage: packer inspect TEMPLATE
 
 Options:
 
+  -format=json                  Output the components of an HCL2 template as JSON.
   -machine-readable             Machine-readable output
   -use-sequential-evaluation    Fallback to using a sequential approach for local/datasource evaluation.
 `
@@ -85,6 +87,7 @@ func (c *InspectCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *InspectCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
+		"-format":           complete.PredictSet("json"),
 		"-machine-readable": complete.PredictNothing,
 	}
 }
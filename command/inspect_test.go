@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -184,3 +185,31 @@ and therefore only show in their raw form here.
 		})
 	}
 }
+
+func TestInspectCommand_jsonFormat(t *testing.T) {
+	c := &InspectCommand{
+		Meta: TestMetaFile(t),
+	}
+	args := []string{"-format=json", "-var=password=hunter2", testFixture("inspect-json")}
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "s3cr3t") {
+		t.Errorf("sensitive values are not redacted:\n%s", out)
+	}
+	if diff := cmp.Diff(testFixtureContent("inspect-json", "expected-output.json"), out); diff != "" {
+		t.Errorf("unexpected output: %s", diff)
+	}
+}
+
+func TestInspectCommand_jsonFormatLegacyTemplate(t *testing.T) {
+	c := &InspectCommand{
+		Meta: TestMetaFile(t),
+	}
+	args := []string{"-format=json", filepath.Join(testFixture("inspect"), "unset_var.json")}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+}
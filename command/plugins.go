@@ -4,8 +4,18 @@
 package command
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"runtime"
 	"strings"
 
+	gversion "github.com/hashicorp/go-version"
+	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+	"github.com/hashicorp/packer/packer/plugin-getter/github"
+	"github.com/hashicorp/packer/packer/plugin-getter/release"
+	pkrversion "github.com/hashicorp/packer/version"
 	"github.com/mitchellh/cli"
 )
 
@@ -33,3 +43,86 @@ Related but not under the "plugins" command :
 func (c *PluginsCommand) Run(args []string) int {
 	return cli.RunResultHelp
 }
+
+// pluginGetters returns the getters used to install remote plugins. When a
+// plugin mirror is configured, either through the PACKER_PLUGIN_MIRROR
+// environment variable or the plugin_mirror setting of the configuration
+// file, it is the only getter used, so that nothing is fetched from the
+// internet.
+func (m *Meta) pluginGetters() ([]plugingetter.Getter, error) {
+	mirror := os.Getenv(release.MirrorEnvVar)
+	if mirror == "" {
+		mirror = m.CoreConfig.Components.PluginConfig.Mirror
+	}
+	if mirror != "" {
+		getter, err := release.NewMirror(mirror)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugin mirror: %s", err)
+		}
+		return []plugingetter.Getter{getter}, nil
+	}
+
+	// the ordering of the getters is important here, place the getter on top which you want to try first
+	return []plugingetter.Getter{
+		&release.Getter{
+			Name: "releases.hashicorp.com",
+		},
+		&github.Getter{
+			// In the past some terraform plugins downloads were blocked from a
+			// specific aws region by s3. Changing the user agent unblocked the
+			// downloads so having one user agent per version will help mitigate
+			// that a little more. Especially in the case someone forks this
+			// code to make it more aggressive or something.
+			// TODO: allow to set this from the config file or an environment
+			// variable.
+			UserAgent: "packer-getter-github-" + pkrversion.String(),
+			Name:      "github.com",
+		},
+	}, nil
+}
+
+// pluginInstallationOptions returns the options used to list and install
+// plugins for the current platform.
+func (m *Meta) pluginInstallationOptions() plugingetter.ListInstallationsOptions {
+	opts := plugingetter.ListInstallationsOptions{
+		PluginDirectory: m.CoreConfig.Components.PluginConfig.PluginDirectory,
+		BinaryInstallationOptions: plugingetter.BinaryInstallationOptions{
+			OS:              runtime.GOOS,
+			ARCH:            runtime.GOARCH,
+			APIVersionMajor: pluginsdk.APIVersionMajor,
+			APIVersionMinor: pluginsdk.APIVersionMinor,
+			Checksummers: []plugingetter.Checksummer{
+				{Type: "sha256", Hash: sha256.New()},
+			},
+			ReleasesOnly: true,
+		},
+	}
+
+	if runtime.GOOS == "windows" {
+		opts.BinaryInstallationOptions.Ext = ".exe"
+	}
+
+	return opts
+}
+
+// releaseChecksums returns the release checksums of every platform for the
+// version of an installed plugin, to be recorded in the lock file. Failing to
+// get them only warns, as the plugin may not come from a release; the lock
+// file is then only verifiable on the current platform.
+func (m *Meta) releaseChecksums(pr *plugingetter.Requirement, install *plugingetter.Installation, opts plugingetter.ListInstallationsOptions, getters []plugingetter.Getter) map[string]string {
+	version, err := gversion.NewVersion(install.Version)
+	if err == nil {
+		var hashes map[string]string
+		hashes, err = pr.ReleaseChecksums(plugingetter.InstallOptions{
+			PluginDirectory:           opts.PluginDirectory,
+			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+			Getters:                   getters,
+		}, version)
+		if err == nil {
+			return hashes
+		}
+	}
+	m.Ui.Error(fmt.Sprintf("Warning: could not get the release checksums of the %q plugin, "+
+		"the lock file will only be verifiable on %s: %s", pr.Identifier, opts.BinaryInstallationOptions.Platform(), err))
+	return nil
+}
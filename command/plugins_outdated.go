@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/go-version"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+type PluginsOutdatedCommand struct {
+	Meta
+}
+
+func (c *PluginsOutdatedCommand) Synopsis() string {
+	return "List required plugins that have newer releases"
+}
+
+func (c *PluginsOutdatedCommand) Help() string {
+	helpText := `
+Usage: packer plugins outdated [options] <path>
+
+  This command lists every plugin required by a Packer config that is not
+  installed at its latest release. For each plugin, it shows the highest
+  installed version matching the packer.required_plugins constraints, the
+  latest release matching those constraints, and the latest release.
+
+  Ex: packer plugins outdated template.pkr.hcl
+  Ex: packer plugins outdated -format=json path/to/folder/
+
+Options:
+  -format=json                  Output the outdated plugins as a JSON list.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+type PluginsOutdatedArgs struct {
+	MetaArgs
+	Format string
+}
+
+func (pa *PluginsOutdatedArgs) AddFlagSets(flags *flag.FlagSet) {
+	flags.StringVar(&pa.Format, "format", "", "output format, only json is supported.")
+	pa.MetaArgs.AddFlagSets(flags)
+}
+
+func (c *PluginsOutdatedCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *PluginsOutdatedCommand) ParseArgs(args []string) (*PluginsOutdatedArgs, int) {
+	var cfg PluginsOutdatedArgs
+	flags := c.Meta.FlagSet("plugins outdated")
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	if cfg.Format != "" && cfg.Format != "json" {
+		c.Ui.Error(fmt.Sprintf("Invalid format %q, only json is supported", cfg.Format))
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Path = args[0]
+	return &cfg, 0
+}
+
+// OutdatedPlugin describes the installed and available versions of a
+// required plugin.
+type OutdatedPlugin struct {
+	Source      string `json:"source"`
+	Constraints string `json:"constraints"`
+	// Current is the highest installed version matching the constraints,
+	// empty when the plugin is not installed.
+	Current string `json:"current"`
+	// LatestAllowed is the latest release matching the constraints.
+	LatestAllowed string `json:"latest_allowed"`
+	// Latest is the latest release.
+	Latest string `json:"latest"`
+}
+
+func (c *PluginsOutdatedCommand) RunContext(buildCtx context.Context, cla *PluginsOutdatedArgs) int {
+	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
+	if ret != 0 {
+		return ret
+	}
+
+	reqs, diags := packerStarter.PluginRequirements()
+	ret = writeDiags(c.Ui, nil, diags)
+	if ret != 0 {
+		return ret
+	}
+
+	getters, err := c.Meta.pluginGetters()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	opts := c.pluginInstallationOptions()
+
+	outdated := []OutdatedPlugin{}
+	for _, pluginRequirement := range reqs {
+		installs, err := pluginRequirement.ListInstallations(opts)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		releases, err := pluginRequirement.ListReleases(plugingetter.InstallOptions{
+			PluginDirectory:           opts.PluginDirectory,
+			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+			Getters:                   getters,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed listing releases of the %q plugin: %s", pluginRequirement.Identifier, err))
+			ret = 1
+			continue
+		}
+
+		var current, latestAllowed, latest *version.Version
+		if len(installs) > 0 {
+			current, _ = version.NewVersion(installs[len(installs)-1].Version)
+		}
+		for _, release := range releases {
+			if release.Prerelease() != "" {
+				continue
+			}
+			latest = release
+			if pluginRequirement.VersionConstraints.Check(release) {
+				latestAllowed = release
+			}
+		}
+
+		if current != nil && (latest == nil || !current.LessThan(latest)) {
+			continue
+		}
+
+		outdated = append(outdated, OutdatedPlugin{
+			Source:        pluginRequirement.Identifier.String(),
+			Constraints:   pluginRequirement.VersionConstraints.String(),
+			Current:       versionString(current),
+			LatestAllowed: versionString(latestAllowed),
+			Latest:        versionString(latest),
+		})
+	}
+
+	if cla.Format == "json" {
+		content, err := json.MarshalIndent(outdated, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to encode outdated plugins: %s", err))
+			return 1
+		}
+		c.Ui.Message(string(content))
+		return ret
+	}
+
+	if len(outdated) == 0 {
+		c.Ui.Message("All required plugins are up to date.")
+		return ret
+	}
+
+	table := &strings.Builder{}
+	w := tabwriter.NewWriter(table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLUGIN\tCONSTRAINTS\tCURRENT\tLATEST ALLOWED\tLATEST")
+	for _, p := range outdated {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Source, p.Constraints,
+			orDash(p.Current), orDash(p.LatestAllowed), orDash(p.Latest))
+	}
+	w.Flush()
+	c.Ui.Message(strings.TrimSuffix(table.String(), "\n"))
+
+	return ret
+}
+
+func versionString(v *version.Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const outdatedTemplate = `
+packer {
+  required_plugins {
+    hashicups = {
+      version = "~> 1.0"
+      source  = "github.com/hashicorp/hashicups"
+    }
+  }
+}
+`
+
+const outdatedIndex = `{
+  "name": "packer-plugin-hashicups",
+  "versions": {
+    "1.0.1": {"name": "packer-plugin-hashicups", "version": "1.0.1"},
+    "1.0.2": {"name": "packer-plugin-hashicups", "version": "1.0.2"},
+    "2.0.0": {"name": "packer-plugin-hashicups", "version": "2.0.0"},
+    "2.1.0-beta": {"name": "packer-plugin-hashicups", "version": "2.1.0-beta"}
+  }
+}`
+
+func testPluginsOutdatedMeta(t *testing.T) (Meta, string) {
+	mirror := t.TempDir()
+	pluginMirror := filepath.Join(mirror, "packer-plugin-hashicups")
+	if err := os.MkdirAll(pluginMirror, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginMirror, "index.json"), []byte(outdatedIndex), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templateDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(templateDir, "plugins.pkr.hcl"), []byte(outdatedTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := TestMetaFile(t)
+	meta.CoreConfig.Components.PluginConfig.PluginDirectory = t.TempDir()
+	meta.CoreConfig.Components.PluginConfig.Mirror = mirror
+	return meta, templateDir
+}
+
+func TestPluginsOutdatedCommand_json(t *testing.T) {
+	meta, templateDir := testPluginsOutdatedMeta(t)
+	c := &PluginsOutdatedCommand{Meta: meta}
+
+	if code := c.Run([]string{"-format=json", templateDir}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	var got []OutdatedPlugin
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %s\n%s", err, out)
+	}
+	expected := []OutdatedPlugin{
+		{
+			Source:        "github.com/hashicorp/hashicups",
+			Constraints:   "~> 1.0",
+			LatestAllowed: "1.0.2",
+			Latest:        "2.0.0",
+		},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("unexpected outdated plugins: %s", diff)
+	}
+}
+
+func TestPluginsOutdatedCommand_table(t *testing.T) {
+	meta, templateDir := testPluginsOutdatedMeta(t)
+	c := &PluginsOutdatedCommand{Meta: meta}
+
+	if code := c.Run([]string{templateDir}); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := GetStdoutAndErrFromTestMeta(t, c.Meta)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and one plugin, got:\n%s", out)
+	}
+	if fields := strings.Fields(lines[1]); !cmp.Equal(fields, []string{"github.com/hashicorp/hashicups", "~>", "1.0", "-", "1.0.2", "2.0.0"}) {
+		t.Fatalf("unexpected plugin line: %q", lines[1])
+	}
+}
+
+func TestPluginsOutdatedCommand_invalidFormat(t *testing.T) {
+	meta, templateDir := testPluginsOutdatedMeta(t)
+	c := &PluginsOutdatedCommand{Meta: meta}
+
+	if code := c.Run([]string{"-format=yaml", templateDir}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+}
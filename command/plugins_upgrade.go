@@ -0,0 +1,186 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/packer/hcl2template/addrs"
+	"github.com/hashicorp/packer/packer"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+type PluginsUpgradeCommand struct {
+	Meta
+}
+
+func (c *PluginsUpgradeCommand) Synopsis() string {
+	return "Upgrade required plugins to their latest allowed version"
+}
+
+func (c *PluginsUpgradeCommand) Help() string {
+	helpText := `
+Usage: packer plugins upgrade [options] <path> [plugin]
+
+  This command installs the latest release of every plugin required by a
+  Packer config that matches its packer.required_plugins constraints. When a
+  plugin source is given, only that plugin is upgraded.
+
+  When the config has a .packer.lock.hcl lock file, the upgraded versions are
+  recorded in it.
+
+  Ex: packer plugins upgrade template.pkr.hcl
+  Ex: packer plugins upgrade path/to/folder/ github.com/hashicorp/amazon
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+type PluginsUpgradeArgs struct {
+	MetaArgs
+	PluginIdentifier string
+}
+
+func (pa *PluginsUpgradeArgs) AddFlagSets(flags *flag.FlagSet) {
+	pa.MetaArgs.AddFlagSets(flags)
+}
+
+func (c *PluginsUpgradeCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *PluginsUpgradeCommand) ParseArgs(args []string) (*PluginsUpgradeArgs, int) {
+	var cfg PluginsUpgradeArgs
+	flags := c.Meta.FlagSet("plugins upgrade")
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		c.Ui.Error(fmt.Sprintf("Invalid arguments, expected either 1 or 2 positional arguments, got %d", len(args)))
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Path = args[0]
+	if len(args) == 2 {
+		cfg.PluginIdentifier = args[1]
+	}
+	return &cfg, 0
+}
+
+func (c *PluginsUpgradeCommand) RunContext(buildCtx context.Context, cla *PluginsUpgradeArgs) int {
+	var only *addrs.Plugin
+	if cla.PluginIdentifier != "" {
+		var err error
+		only, err = addrs.ParsePluginSourceString(cla.PluginIdentifier)
+		if err != nil {
+			c.Ui.Errorf("Invalid source string %q: %s", cla.PluginIdentifier, err)
+			return 1
+		}
+	}
+
+	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
+	if ret != 0 {
+		return ret
+	}
+
+	reqs, diags := packerStarter.PluginRequirements()
+	ret = writeDiags(c.Ui, nil, diags)
+	if ret != 0 {
+		return ret
+	}
+
+	getters, err := c.Meta.pluginGetters()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	opts := c.pluginInstallationOptions()
+
+	ui := &packer.ColoredUi{
+		Color: packer.UiColorCyan,
+		Ui:    c.Ui,
+	}
+
+	lockPath := plugingetter.LockFilePath(cla.Path)
+	lock, err := plugingetter.ReadLockFile(lockPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read lock file %q: %s", lockPath, err))
+		return 1
+	}
+	lockExists := lock.Exists()
+
+	found := false
+	for _, pluginRequirement := range reqs {
+		if only != nil && pluginRequirement.Identifier.String() != only.String() {
+			continue
+		}
+		found = true
+
+		newInstall, err := pluginRequirement.InstallLatest(plugingetter.InstallOptions{
+			PluginDirectory:           opts.PluginDirectory,
+			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+			Getters:                   getters,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed upgrading the %q plugin:", pluginRequirement.Identifier))
+			c.Ui.Error(err.Error())
+			ret = 1
+			continue
+		}
+		if newInstall != nil {
+			ui.Say(fmt.Sprintf("Installed plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath))
+		} else {
+			ui.Say(fmt.Sprintf("Plugin %s is up to date", pluginRequirement.Identifier))
+		}
+
+		if !lockExists {
+			continue
+		}
+		installs, err := pluginRequirement.ListInstallations(opts)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		if len(installs) == 0 {
+			continue
+		}
+		install := installs[len(installs)-1]
+		zipHashes := c.releaseChecksums(pluginRequirement, install, opts, getters)
+		if err := lock.Record(pluginRequirement, install, opts.BinaryInstallationOptions, zipHashes); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to lock the %q plugin: %s", pluginRequirement.Identifier, err))
+			ret = 1
+		}
+	}
+
+	if only != nil && !found {
+		c.Ui.Error(fmt.Sprintf("The %q plugin is not required by %q", only, cla.Path))
+		return 1
+	}
+
+	if lockExists {
+		lock.Retain(reqs)
+		if err := lock.Write(lockPath); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write lock file %q: %s", lockPath, err))
+			return 1
+		}
+	}
+
+	return ret
+}
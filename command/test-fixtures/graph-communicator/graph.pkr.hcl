@@ -0,0 +1,18 @@
+variable "bastion_host" {
+  type    = string
+  default = "bastion.example.com"
+}
+
+communicator "ssh" "bastion" {
+  ssh_username     = "packer"
+  ssh_bastion_host = var.bastion_host
+}
+
+source "null" "web" {
+  communicator = communicator.ssh.bastion
+  ssh_host     = "10.0.0.1"
+}
+
+build {
+  sources = ["source.null.web"]
+}
@@ -0,0 +1,34 @@
+variable "name" {
+  type    = string
+  default = "graph"
+}
+
+data "null" "base" {
+  input = var.name
+}
+
+locals {
+  target = "${data.null.base.output}.txt"
+}
+
+source "file" "example" {
+  content = "hello"
+  target  = local.target
+}
+
+build {
+  name    = "example"
+  sources = ["source.file.example"]
+
+  provisioner "shell-local" {
+    inline = ["echo ${var.name}"]
+  }
+
+  provisioner "shell-local" {
+    inline = ["echo ${local.target}"]
+  }
+
+  post-processor "manifest" {
+    output = "${local.target}.json"
+  }
+}
@@ -0,0 +1,16 @@
+data "null" "disk" {
+  input = "small"
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["source.null.test"]
+
+  precondition {
+    condition     = data.null.disk.output == "large"
+    error_message = "The disk must be large."
+  }
+}
@@ -0,0 +1,17 @@
+variable "disk_size" {
+  type    = number
+  default = 5
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["source.null.test"]
+
+  precondition {
+    condition     = var.disk_size >= 10
+    error_message = "The disk_size value must be at least 10."
+  }
+}
@@ -0,0 +1,71 @@
+variable "name" {
+  type        = string
+  default     = "example"
+  description = "Name of the image."
+
+  validation {
+    condition     = length(var.name) > 3
+    error_message = "The name must be longer than 3 characters."
+  }
+}
+
+variable "password" {
+  type      = string
+  sensitive = true
+}
+
+data "null" "base" {
+  input = var.name
+}
+
+locals {
+  target = "${data.null.base.output}.txt"
+  tags   = { name = var.name }
+}
+
+local "secret" {
+  expression = "s3cr3t"
+  sensitive  = true
+}
+
+source "file" "example" {
+  content = "hello"
+  target  = local.target
+}
+
+build {
+  name        = "example"
+  description = "Builds the example."
+
+  source "file.example" {
+    name = "renamed"
+  }
+  sources = ["source.file.example"]
+
+  provisioner "shell-local" {
+    inline = ["echo first"]
+  }
+
+  provisioner "shell-local" {
+    name   = "second"
+    only   = ["file.renamed"]
+    inline = ["echo second"]
+  }
+
+  error-cleanup-provisioner "shell-local" {
+    inline = ["echo cleanup"]
+  }
+
+  post-processor "manifest" {}
+
+  post-processors {
+    post-processor "shell-local" {
+      except = ["file.example"]
+      inline = ["echo post"]
+    }
+    post-processor "manifest" {
+      name                = "final"
+      keep_input_artifact = true
+    }
+  }
+}
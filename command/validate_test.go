@@ -28,6 +28,10 @@ func TestValidateCommand(t *testing.T) {
 		{path: filepath.Join(testFixture("validate"), "var_foo_with_no_default.pkr.hcl"), exitCode: 1},
 
 		{path: testFixture("hcl", "validation", "wrong_pause_before.pkr.hcl"), exitCode: 1},
+		{path: testFixture("hcl", "validation", "failing_precondition.pkr.hcl"), exitCode: 1},
+		// preconditions referring to unknown data sources are checked when the build runs
+		{path: testFixture("hcl", "validation", "datasource_precondition.pkr.hcl"), exitCode: 0},
+		{path: testFixture("hcl", "validation", "datasource_precondition.pkr.hcl"), exitCode: 1, extraArgs: []string{"--evaluate-datasources"}},
 
 		// wrong version fails
 		{path: filepath.Join(testFixture("version_req", "base_failure")), exitCode: 1},
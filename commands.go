@@ -47,6 +47,12 @@ func init() {
 			}, nil
 		},
 
+		"graph": func() (cli.Command, error) {
+			return &command.GraphCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
 		"hcl2_upgrade": func() (cli.Command, error) {
 			return &command.HCL2UpgradeCommand{
 				Meta: *CommandMeta,
@@ -83,6 +89,12 @@ func init() {
 			}, nil
 		},
 
+		"plugins outdated": func() (cli.Command, error) {
+			return &command.PluginsOutdatedCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
 		"plugins remove": func() (cli.Command, error) {
 			return &command.PluginsRemoveCommand{
 				Meta: *CommandMeta,
@@ -95,6 +107,12 @@ func init() {
 			}, nil
 		},
 
+		"plugins upgrade": func() (cli.Command, error) {
+			return &command.PluginsUpgradeCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
 		"validate": func() (cli.Command, error) {
 			return &command.ValidateCommand{
 				Meta: *CommandMeta,
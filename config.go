@@ -22,6 +22,7 @@ type config struct {
 	RawBuilders                map[string]string `json:"builders"`
 	RawProvisioners            map[string]string `json:"provisioners"`
 	RawPostProcessors          map[string]string `json:"post-processors"`
+	PluginMirror               string            `json:"plugin_mirror"`
 
 	Plugins *packer.PluginConfig
 }
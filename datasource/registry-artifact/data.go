@@ -0,0 +1,253 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type DatasourceOutput,Config
+package registry_artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	"github.com/hashicorp/packer-plugin-sdk/hcl2helper"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer/internal/hcp/env"
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+)
+
+type Datasource struct {
+	config Config
+}
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	// The location of the self-hosted registry: a local directory, or the
+	// URL of an HTTP registry. Defaults to the value of the
+	// `HCP_PACKER_REGISTRY_LOCATION` environment variable.
+	Location string `mapstructure:"location" required:"false"`
+
+	// The name of the bucket your artifact is in.
+	BucketName string `mapstructure:"bucket_name" required:"true"`
+
+	// The name of the channel to use when retrieving your artifact.
+	// Either `channel_name` or `version_fingerprint` MUST be set.
+	ChannelName string `mapstructure:"channel_name" required:"true"`
+
+	// The fingerprint of the version to use when retrieving your artifact.
+	// Either this or `channel_name` MUST be set.
+	// Mutually exclusive with `channel_name`
+	VersionFingerprint string `mapstructure:"version_fingerprint" required:"true"`
+
+	// The name of the platform that your artifact is for.
+	// For example, "aws", "azure", or "docker".
+	Platform string `mapstructure:"platform" required:"true"`
+
+	// The name of the region your artifact is in.
+	// For example "us-east-1".
+	Region string `mapstructure:"region" required:"true"`
+
+	// The specific Packer builder used to create the artifact.
+	// For example, "amazon-ebs.example"
+	ComponentType string `mapstructure:"component_type" required:"false"`
+}
+
+func (d *Datasource) ConfigSpec() hcldec.ObjectSpec {
+	return d.config.FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Configure(raws ...interface{}) error {
+	err := config.Decode(&d.config, nil, raws...)
+	if err != nil {
+		return err
+	}
+
+	if d.config.Location == "" {
+		d.config.Location = os.Getenv(env.HCPPackerRegistryLocation)
+	}
+
+	var errs *packersdk.MultiError
+
+	if d.config.Location == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf(
+			"the `location` must be specified, or set with the %s environment variable",
+			env.HCPPackerRegistryLocation,
+		))
+	}
+
+	if d.config.BucketName == "" {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("the `bucket_name` must be specified"),
+		)
+	}
+
+	// Ensure either channel_name or version_fingerprint is set, and not both at the same time.
+	if d.config.ChannelName == "" && d.config.VersionFingerprint == "" {
+		errs = packersdk.MultiErrorAppend(errs, errors.New(
+			"`version_fingerprint` or `channel_name` must be specified",
+		))
+	}
+	if d.config.ChannelName != "" && d.config.VersionFingerprint != "" {
+		errs = packersdk.MultiErrorAppend(errs, errors.New(
+			"`version_fingerprint` and `channel_name` cannot be specified together",
+		))
+	}
+
+	if d.config.Region == "" {
+		errs = packersdk.MultiErrorAppend(errs,
+			fmt.Errorf("the `region` must be specified"),
+		)
+	}
+
+	if d.config.Platform == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf(
+			"the `platform` must be specified",
+		))
+	}
+
+	if errs != nil && len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type DatasourceOutput struct {
+	// The name of the platform that the artifact exists in.
+	// For example, "aws", "azure", or "docker".
+	Platform string `mapstructure:"platform"`
+
+	// The specific Packer builder or post-processor used to create the artifact.
+	ComponentType string `mapstructure:"component_type"`
+
+	// The date and time at which the artifact was created.
+	CreatedAt string `mapstructure:"created_at"`
+
+	// The ID of the build that created the artifact.
+	BuildID string `mapstructure:"build_id"`
+
+	// The ID of the version the build belongs to.
+	VersionID string `mapstructure:"version_id"`
+
+	// The fingerprint of the version the build belongs to.
+	VersionFingerprint string `mapstructure:"version_fingerprint"`
+
+	// The name of the channel used to query the version. This value will be empty if the `version_fingerprint` was
+	// used directly instead of a channel.
+	ChannelName string `mapstructure:"channel_name"`
+
+	// The UUID associated with the Packer run that created this artifact.
+	PackerRunUUID string `mapstructure:"packer_run_uuid"`
+
+	// Identifier or URL of the remote artifact as given by a build.
+	// For example, ami-12345.
+	ExternalIdentifier string `mapstructure:"external_identifier"`
+
+	// The region as given by `packer build`. eg. "ap-east-1".
+	// For locally managed clouds, this may map instead to a cluster, server or datastore.
+	Region string `mapstructure:"region"`
+
+	// The key:value metadata labels associated with this build.
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+func (d *Datasource) OutputSpec() hcldec.ObjectSpec {
+	return (&DatasourceOutput{}).FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Execute() (cty.Value, error) {
+	ctx := context.TODO()
+
+	store, err := selfhosted.New(d.config.Location, os.Getenv(env.HCPPackerRegistryToken))
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+
+	fingerprint := d.config.VersionFingerprint
+	if d.config.ChannelName != "" {
+		log.Printf(
+			"[INFO] Reading info from registry %s [bucket=%s, channel=%s]",
+			d.config.Location, d.config.BucketName, d.config.ChannelName,
+		)
+
+		channel, err := store.GetChannel(ctx, d.config.BucketName, d.config.ChannelName)
+		if err != nil {
+			return cty.NullVal(cty.EmptyObject), fmt.Errorf(
+				"error retrieving channel from registry: %s", err,
+			)
+		}
+		if channel.VersionFingerprint == "" {
+			return cty.NullVal(cty.EmptyObject), fmt.Errorf(
+				"there is no version associated with the channel %s", d.config.ChannelName,
+			)
+		}
+		fingerprint = channel.VersionFingerprint
+	}
+
+	log.Printf(
+		"[INFO] Reading info from registry %s [bucket=%s, version_fingerprint=%s]",
+		d.config.Location, d.config.BucketName, fingerprint,
+	)
+	version, err := store.GetVersion(ctx, d.config.BucketName, fingerprint)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf(
+			"error retrieving version from registry: %s", err,
+		)
+	}
+
+	cloudAndRegions := map[string][]string{}
+	for _, name := range sortedBuildNames(version) {
+		build := version.Builds[name]
+		if build.Platform != d.config.Platform || build.Status != selfhosted.BuildStatusDone {
+			continue
+		}
+		for _, artifact := range build.Artifacts {
+			cloudAndRegions[build.Platform] = append(cloudAndRegions[build.Platform], artifact.Region)
+			if artifact.Region != d.config.Region {
+				continue
+			}
+			if d.config.ComponentType != "" && build.ComponentType != d.config.ComponentType {
+				continue
+			}
+			output := DatasourceOutput{
+				Platform:           build.Platform,
+				ComponentType:      build.ComponentType,
+				CreatedAt:          artifact.CreatedAt.String(),
+				BuildID:            build.ID,
+				VersionID:          version.ID,
+				VersionFingerprint: version.Fingerprint,
+				ChannelName:        d.config.ChannelName,
+				PackerRunUUID:      build.PackerRunUUID,
+				ExternalIdentifier: artifact.ExternalIdentifier,
+				Region:             artifact.Region,
+				Labels:             build.Labels,
+			}
+			return hcl2helper.HCL2ValueFromConfig(output, d.OutputSpec()), nil
+		}
+	}
+
+	return cty.NullVal(cty.EmptyObject), fmt.Errorf(
+		"could not find a build result matching "+
+			"[region=%q, platform=%q, component_type=%q]. Available: %v ",
+		d.config.Region, d.config.Platform, d.config.ComponentType, cloudAndRegions,
+	)
+}
+
+// sortedBuildNames returns the names of the builds of the version in order, so
+// that the same artifact is returned when several match.
+func sortedBuildNames(version *selfhosted.Version) []string {
+	names := make([]string, 0, len(version.Builds))
+	for name := range version.Builds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
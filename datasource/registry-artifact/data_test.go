@@ -0,0 +1,141 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package registry_artifact
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+)
+
+func TestDatasource(t *testing.T) {
+	dir := t.TempDir()
+	store := selfhosted.NewDirStore(dir)
+	ctx := context.Background()
+	if err := store.PutBucket(ctx, &selfhosted.Bucket{Name: "ubuntu"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutVersion(ctx, "ubuntu", &selfhosted.Version{ID: "v1", Fingerprint: "fp1"}); err != nil {
+		t.Fatal(err)
+	}
+	builds := []*selfhosted.Build{
+		{
+			ID:            "b1",
+			ComponentType: "docker.ubuntu",
+			Platform:      "docker",
+			Status:        selfhosted.BuildStatusDone,
+			Labels:        map[string]string{"os": "ubuntu"},
+			Artifacts:     []selfhosted.Artifact{{ExternalIdentifier: "sha256:1234", Region: "local"}},
+		},
+		{
+			ID:            "b2",
+			ComponentType: "amazon-ebs.ubuntu",
+			Platform:      "aws",
+			Status:        selfhosted.BuildStatusFailed,
+			Artifacts:     []selfhosted.Artifact{{ExternalIdentifier: "ami-1234", Region: "us-east-1"}},
+		},
+	}
+	for _, b := range builds {
+		if err := store.PutBuild(ctx, "ubuntu", "fp1", b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.PutChannel(ctx, "ubuntu", &selfhosted.Channel{Name: "latest", VersionID: "v1", VersionFingerprint: "fp1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantID  string
+		wantErr string
+	}{
+		{
+			name: "by channel",
+			config: map[string]interface{}{
+				"location": dir, "bucket_name": "ubuntu", "channel_name": "latest",
+				"platform": "docker", "region": "local",
+			},
+			wantID: "sha256:1234",
+		},
+		{
+			name: "by fingerprint",
+			config: map[string]interface{}{
+				"location": dir, "bucket_name": "ubuntu", "version_fingerprint": "fp1",
+				"platform": "docker", "region": "local", "component_type": "docker.ubuntu",
+			},
+			wantID: "sha256:1234",
+		},
+		{
+			name: "failed builds are ignored",
+			config: map[string]interface{}{
+				"location": dir, "bucket_name": "ubuntu", "channel_name": "latest",
+				"platform": "aws", "region": "us-east-1",
+			},
+			wantErr: "could not find a build result",
+		},
+		{
+			name: "missing channel",
+			config: map[string]interface{}{
+				"location": dir, "bucket_name": "ubuntu", "channel_name": "stable",
+				"platform": "docker", "region": "local",
+			},
+			wantErr: "error retrieving channel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Datasource{}
+			if err := d.Configure(tt.config); err != nil {
+				t.Fatalf("Configure: %s", err)
+			}
+			val, err := d.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute: %s", err)
+			}
+			if got := val.GetAttr("external_identifier").AsString(); got != tt.wantID {
+				t.Errorf("expected artifact %q, got %q", tt.wantID, got)
+			}
+			if got := val.GetAttr("version_fingerprint").AsString(); got != "fp1" {
+				t.Errorf("expected version fingerprint fp1, got %q", got)
+			}
+		})
+	}
+}
+
+func TestDatasource_Configure(t *testing.T) {
+	t.Setenv("HCP_PACKER_REGISTRY_LOCATION", "")
+	d := &Datasource{}
+	err := d.Configure(map[string]interface{}{
+		"bucket_name": "ubuntu", "channel_name": "latest", "version_fingerprint": "fp1",
+	})
+	if err == nil {
+		t.Fatal("Configure should fail")
+	}
+	for _, want := range []string{"location", "cannot be specified together", "region", "platform"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error %q", want, err)
+		}
+	}
+
+	t.Setenv("HCP_PACKER_REGISTRY_LOCATION", "https://registry.example.com")
+	d = &Datasource{}
+	err = d.Configure(map[string]interface{}{
+		"bucket_name": "ubuntu", "channel_name": "latest", "platform": "docker", "region": "local",
+	})
+	if err != nil {
+		t.Fatalf("Configure: %s", err)
+	}
+	if d.config.Location != "https://registry.example.com" {
+		t.Errorf("expected the location of the environment, got %q", d.config.Location)
+	}
+}
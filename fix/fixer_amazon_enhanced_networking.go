@@ -6,6 +6,7 @@ package fix
 import (
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -67,3 +68,10 @@ func (FixerAmazonEnhancedNetworking) Fix(input map[string]interface{}) (map[stri
 func (FixerAmazonEnhancedNetworking) Synopsis() string {
 	return `Replaces "enhanced_networking" in builders with "ena_support"`
 }
+
+func (FixerAmazonEnhancedNetworking) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, hasTypePrefix("amazon-")) {
+		renameAttribute(body, "enhanced_networking", "ena_support")
+	}
+	return nil
+}
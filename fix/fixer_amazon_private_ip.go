@@ -8,7 +8,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FixerAmazonPrivateIP is a Fixer that replaces instances of `"private_ip":
@@ -81,3 +83,23 @@ func (FixerAmazonPrivateIP) Fix(input map[string]interface{}) (map[string]interf
 func (FixerAmazonPrivateIP) Synopsis() string {
 	return "Replaces `\"ssh_private_ip\": true` in amazon builders with `\"ssh_interface\": \"private_ip\"`"
 }
+
+func (FixerAmazonPrivateIP) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, hasTypePrefix("amazon-")) {
+		attr := body.GetAttribute("ssh_private_ip")
+		if attr == nil || body.GetAttribute("ssh_interface") != nil {
+			continue
+		}
+		privateIP, ok := boolLiteral(attr)
+		if !ok {
+			continue
+		}
+		sshInterface := "public_ip"
+		if privateIP {
+			sshInterface = "private_ip"
+		}
+		body.RenameAttribute("ssh_private_ip", "ssh_interface")
+		body.SetAttributeValue("ssh_interface", cty.StringVal(sshInterface))
+	}
+	return nil
+}
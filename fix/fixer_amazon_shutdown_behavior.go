@@ -6,6 +6,7 @@ package fix
 import (
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -61,3 +62,10 @@ func (FixerAmazonShutdownBehavior) Fix(input map[string]interface{}) (map[string
 func (FixerAmazonShutdownBehavior) Synopsis() string {
 	return `Changes "shutdown_behaviour" to "shutdown_behavior" in Amazon builders.`
 }
+
+func (FixerAmazonShutdownBehavior) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, hasTypePrefix("amazon-")) {
+		renameAttribute(body, "shutdown_behaviour", "shutdown_behavior")
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package fix
 import (
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -73,3 +74,14 @@ func (FixerAzureExcludeFromLatest) Fix(input map[string]interface{}) (map[string
 func (FixerAzureExcludeFromLatest) Synopsis() string {
 	return `Changes "exlude_from_latest" to "exclude_from_latest" in Azure builders.`
 }
+
+func (FixerAzureExcludeFromLatest) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, hasTypePrefix("azure-chroot")) {
+		for _, block := range body.Blocks() {
+			if block.Type() == "shared_image_destination" {
+				renameAttribute(block.Body(), "exlude_from_latest", "exclude_from_latest")
+			}
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package fix
 import (
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -94,3 +95,12 @@ func (FixerCommConfig) Fix(input map[string]interface{}) (map[string]interface{}
 func (FixerCommConfig) Synopsis() string {
 	return `Remove ssh prefixes from communicator port forwarding configuration (host_port_min, host_port_max, skip_nat_mapping)`
 }
+
+func (FixerCommConfig) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, hasTypePrefix("virtualbox")) {
+		renameAttribute(body, "ssh_host_port_min", "host_port_min")
+		renameAttribute(body, "ssh_host_port_max", "host_port_max")
+		renameAttribute(body, "ssh_skip_nat_mapping", "skip_nat_mapping")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -73,3 +74,10 @@ func (FixerGalaxyCommand) Fix(input map[string]interface{}) (map[string]interfac
 func (FixerGalaxyCommand) Synopsis() string {
 	return `Replaces "galaxycommand" in ansible-local provisioner configs with "galaxy_command"`
 }
+
+func (FixerGalaxyCommand) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Provisioners(f, isType("ansible-local")) {
+		renameAttribute(body, "galaxycommand", "galaxy_command")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -64,3 +65,11 @@ func (FizerHypervCPUandRAM) Synopsis() string {
 	return `Replaces "cpu" with "cpus" and "ram_size" with "memory"` +
 		`in Hyper-V VMCX builder templates`
 }
+
+func (FizerHypervCPUandRAM) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("hyperv-vmcx", "hyperv-iso")) {
+		renameAttribute(body, "cpu", "cpus")
+		renameAttribute(body, "ram_size", "memory")
+	}
+	return nil
+}
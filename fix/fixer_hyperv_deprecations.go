@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -57,3 +58,10 @@ func (FixerHypervDeprecations) Fix(input map[string]interface{}) (map[string]int
 func (FixerHypervDeprecations) Synopsis() string {
 	return `Removes the deprecated "vhd_temp_path" setting from Hyper-V ISO builder templates`
 }
+
+func (FixerHypervDeprecations) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("hyperv-iso")) {
+		body.RemoveAttribute("vhd_temp_path")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -59,3 +60,10 @@ func (FixerHypervVmxcTypo) Synopsis() string {
 	return `Fixes a typo replacing "clone_from_vmxc_path" with "clone_from_vmcx_path" ` +
 		`in Hyper-V VMCX builder templates`
 }
+
+func (FixerHypervVmxcTypo) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("hyperv-vmcx")) {
+		renameAttribute(body, "clone_from_vmxc_path", "clone_from_vmcx_path")
+	}
+	return nil
+}
@@ -4,7 +4,9 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FixerISOChecksumTypeAndURL is a Fixer that remove the "iso_checksum_url" and
@@ -64,3 +66,36 @@ func stringValue(v interface{}) string {
 func (FixerISOChecksumTypeAndURL) Synopsis() string {
 	return `Puts content of potential "iso_checksum_url" and "iso_checksum_url" in "iso_checksum"`
 }
+
+func (FixerISOChecksumTypeAndURL) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, anyType) {
+		checksumURL := body.GetAttribute("iso_checksum_url")
+		checksumType := body.GetAttribute("iso_checksum_type")
+		checksum := body.GetAttribute("iso_checksum")
+		if checksumURL == nil && checksumType == nil {
+			continue
+		}
+
+		switch {
+		case checksumURL != nil:
+			tokens := templateTokens(stringTokens("file:"), expressionTokens(checksumURL))
+			if checksum != nil {
+				body.SetAttributeRaw("iso_checksum", tokens)
+				body.RemoveAttribute("iso_checksum_url")
+			} else {
+				body.RenameAttribute("iso_checksum_url", "iso_checksum")
+				body.SetAttributeRaw("iso_checksum", tokens)
+			}
+		case checksum != nil:
+			if t, ok := stringLiteral(checksumType); ok && t == "none" {
+				body.SetAttributeValue("iso_checksum", cty.StringVal("none"))
+				break
+			}
+			body.SetAttributeRaw("iso_checksum", templateTokens(
+				expressionTokens(checksumType), stringTokens(":"), expressionTokens(checksum)))
+		}
+		body.RemoveAttribute("iso_checksum_url")
+		body.RemoveAttribute("iso_checksum_type")
+	}
+	return nil
+}
@@ -4,7 +4,9 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FixerISOMD5 is a Fixer that replaces the "iso_md5" configuration key
@@ -54,3 +56,14 @@ func (FixerISOMD5) Fix(input map[string]interface{}) (map[string]interface{}, er
 func (FixerISOMD5) Synopsis() string {
 	return `Replaces "iso_md5" in builders with "iso_checksum"`
 }
+
+func (FixerISOMD5) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, anyType) {
+		if body.GetAttribute("iso_md5") == nil {
+			continue
+		}
+		body.RenameAttribute("iso_md5", "iso_checksum")
+		body.SetAttributeValue("iso_checksum_type", cty.StringVal("md5"))
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -65,3 +66,11 @@ func (FixerParallelsDeprecations) Fix(input map[string]interface{}) (map[string]
 func (FixerParallelsDeprecations) Synopsis() string {
 	return `Removes deprecated "parallels_tools_host_path" from Parallels builders and changes "guest_os_distribution" to "guest_os_type".`
 }
+
+func (FixerParallelsDeprecations) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("parallels-iso", "parallels-pvm")) {
+		body.RemoveAttribute("parallels_tools_host_path")
+		renameAttribute(body, "guest_os_distribution", "guest_os_type")
+	}
+	return nil
+}
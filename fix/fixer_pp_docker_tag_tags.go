@@ -6,7 +6,10 @@ package fix
 import (
 	"strings"
 
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FixerDockerTagtoTags renames tag to tags
@@ -113,3 +116,36 @@ func (FixerDockerTagtoTags) Fix(input map[string]interface{}) (map[string]interf
 func (FixerDockerTagtoTags) Synopsis() string {
 	return `Updates "docker" post-processor so any "tag" field is renamed to "tags".`
 }
+
+func (FixerDockerTagtoTags) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2PostProcessors(f, isType("docker-tag")) {
+		tag := body.GetAttribute("tag")
+		if tag == nil {
+			continue
+		}
+
+		var list hclwrite.Tokens
+		if s, ok := stringLiteral(tag); ok {
+			var tags []cty.Value
+			for _, t := range strings.Split(s, ",") {
+				tags = append(tags, cty.StringVal(strings.TrimSpace(t)))
+			}
+			list = hclwrite.TokensForValue(cty.TupleVal(tags))
+		} else if tokens := expressionTokens(tag); tokens[0].Type == hclsyntax.TokenOBrack {
+			list = tokens
+		} else {
+			list = hclwrite.TokensForTuple([]hclwrite.Tokens{tokens})
+		}
+
+		if tags := body.GetAttribute("tags"); tags != nil {
+			list = hclwrite.TokensForFunctionCall("distinct",
+				hclwrite.TokensForFunctionCall("concat", expressionTokens(tags), list))
+			body.SetAttributeRaw("tags", list)
+			body.RemoveAttribute("tag")
+			continue
+		}
+		body.RenameAttribute("tag", "tags")
+		body.SetAttributeRaw("tags", list)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -66,3 +67,10 @@ func (FixerManifestFilename) Fix(input map[string]interface{}) (map[string]inter
 func (FixerManifestFilename) Synopsis() string {
 	return `Updates "manifest" post-processor so any "filename" field is renamed to "output".`
 }
+
+func (FixerManifestFilename) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2PostProcessors(f, isType("manifest")) {
+		renameAttribute(body, "filename", "output")
+	}
+	return nil
+}
@@ -4,6 +4,10 @@
 package fix
 
 import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -50,3 +54,59 @@ func (FixerProxmoxType) Fix(input map[string]interface{}) (map[string]interface{
 func (FixerProxmoxType) Synopsis() string {
 	return `Updates the builder type proxmox to proxmox-iso`
 }
+
+// FixHCL2 renames the proxmox source blocks, and updates the references to
+// them in the builds.
+func (FixerProxmoxType) FixHCL2(f *hclwrite.File) error {
+	for _, block := range f.Body().Blocks() {
+		if labels := block.Labels(); block.Type() == "source" && len(labels) == 2 && labels[0] == "proxmox" {
+			block.SetLabels([]string{"proxmox-iso", labels[1]})
+		}
+	}
+
+	var bodies []*hclwrite.Body
+	for _, build := range buildBlocks(f) {
+		bodies = append(bodies, build.Body())
+		for _, block := range build.Body().Blocks() {
+			if labels := block.Labels(); block.Type() == "source" && len(labels) == 1 && strings.HasPrefix(labels[0], "proxmox.") {
+				block.SetLabels([]string{"proxmox-iso." + strings.TrimPrefix(labels[0], "proxmox.")})
+			}
+			bodies = append(bodies, block.Body())
+			if block.Type() == "post-processors" {
+				for _, inner := range block.Body().Blocks() {
+					bodies = append(bodies, inner.Body())
+				}
+			}
+		}
+	}
+	// sources reference source.proxmox.name, only and except reference
+	// proxmox.name.
+	for _, body := range bodies {
+		for _, name := range []string{"sources", "only", "except"} {
+			attr := body.GetAttribute(name)
+			if attr == nil {
+				continue
+			}
+			tokens := expressionTokens(attr)
+			changed := false
+			for i, t := range tokens {
+				if t.Type != hclsyntax.TokenQuotedLit {
+					continue
+				}
+				for _, prefix := range []string{"source.proxmox.", "proxmox."} {
+					if lit := string(t.Bytes); strings.HasPrefix(lit, prefix) {
+						renamed := *t
+						renamed.Bytes = []byte(strings.Replace(lit, "proxmox.", "proxmox-iso.", 1))
+						tokens[i] = &renamed
+						changed = true
+						break
+					}
+				}
+			}
+			if changed {
+				body.SetAttributeRaw(name, tokens)
+			}
+		}
+	}
+	return nil
+}
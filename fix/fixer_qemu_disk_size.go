@@ -6,7 +6,10 @@ package fix
 import (
 	"strconv"
 
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // FixerQEMUDiskSize updates disk_size from a string to int for QEMU builders
@@ -58,3 +61,22 @@ func (FixerQEMUDiskSize) Fix(input map[string]interface{}) (map[string]interface
 func (FixerQEMUDiskSize) Synopsis() string {
 	return `Updates "disk_size" from int to string in QEMU builders.`
 }
+
+func (FixerQEMUDiskSize) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("qemu")) {
+		attr := body.GetAttribute("disk_size")
+		if attr == nil {
+			continue
+		}
+		tokens := expressionTokens(attr)
+		if len(tokens) != 1 || tokens[0].Type != hclsyntax.TokenNumberLit {
+			continue
+		}
+		diskSize, err := strconv.Atoi(string(tokens[0].Bytes))
+		if err != nil {
+			continue
+		}
+		body.SetAttributeValue("disk_size", cty.StringVal(strconv.Itoa(diskSize)+"M"))
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -64,3 +65,11 @@ func (FixerQEMUHostPort) DeprecatedOptions() map[string][]string {
 		"transcend.qemu": []string{"ssh_host_port_max", "ssh_host_port_min"},
 	}
 }
+
+func (FixerQEMUHostPort) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("qemu")) {
+		renameAttribute(body, "ssh_host_port_min", "host_port_min")
+		renameAttribute(body, "ssh_host_port_max", "host_port_max")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -61,3 +62,10 @@ func (FixerScalewayAccessKey) Fix(input map[string]interface{}) (map[string]inte
 func (FixerScalewayAccessKey) Synopsis() string {
 	return `Updates builders using "access_key" to use "organization_id"`
 }
+
+func (FixerScalewayAccessKey) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, isType("scaleway")) {
+		renameAttribute(body, "access_key", "organization_id")
+	}
+	return nil
+}
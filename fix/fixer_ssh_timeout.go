@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -68,3 +69,10 @@ func (FixerSSHTimout) Fix(input map[string]interface{}) (map[string]interface{},
 func (FixerSSHTimout) Synopsis() string {
 	return `Replaces "ssh_wait_timeout" with "ssh_timeout"`
 }
+
+func (FixerSSHTimout) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, anyType) {
+		renameAttribute(body, "ssh_wait_timeout", "ssh_timeout")
+	}
+	return nil
+}
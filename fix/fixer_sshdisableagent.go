@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -57,3 +58,10 @@ func (FixerSSHDisableAgent) Fix(input map[string]interface{}) (map[string]interf
 func (FixerSSHDisableAgent) Synopsis() string {
 	return `Updates builders using "ssh_disable_agent" to use "ssh_disable_agent_forwarding"`
 }
+
+func (FixerSSHDisableAgent) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, anyType) {
+		renameAttribute(body, "ssh_disable_agent", "ssh_disable_agent_forwarding")
+	}
+	return nil
+}
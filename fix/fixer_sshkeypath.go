@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -57,3 +58,10 @@ func (FixerSSHKeyPath) Fix(input map[string]interface{}) (map[string]interface{}
 func (FixerSSHKeyPath) Synopsis() string {
 	return `Updates builders using "ssh_key_path" to use "ssh_private_key_file"`
 }
+
+func (FixerSSHKeyPath) FixHCL2(f *hclwrite.File) error {
+	for _, body := range hcl2Sources(f, anyType) {
+		renameAttribute(body, "ssh_key_path", "ssh_private_key_file")
+	}
+	return nil
+}
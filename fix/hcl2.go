@@ -0,0 +1,257 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// An HCL2Fixer is a Fixer that can also perform its fix operation on an HCL2
+// configuration file.
+type HCL2Fixer interface {
+	// FixHCL2 rewrites the blocks of the file in place. Fixers only rewrite
+	// the expressions they can understand, and leave the other ones as is.
+	FixHCL2(f *hclwrite.File) error
+}
+
+// HCL2FixerOrder returns the names of the fixers that can fix HCL2
+// configuration files, in the order they should be run.
+func HCL2FixerOrder() []string {
+	var names []string
+	for _, name := range FixerOrder {
+		if _, ok := Fixers[name].(HCL2Fixer); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FixHCL2 runs all the HCL2 fixers on the source of an HCL2 configuration
+// file, and returns the fixed source. Comments are preserved, and fixed files
+// are formatted like `packer fmt` does; src is returned as is when there is
+// nothing to fix.
+func FixHCL2(src []byte, filename string) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	for _, name := range HCL2FixerOrder() {
+		if err := Fixers[name].(HCL2Fixer).FixHCL2(f); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	fixed := f.Bytes()
+	if bytes.Equal(fixed, hclwrite.Format(src)) {
+		return src, nil
+	}
+	return fixed, nil
+}
+
+// anyType matches every component type.
+func anyType(string) bool { return true }
+
+// isType returns a matcher for the given component types.
+func isType(types ...string) func(string) bool {
+	return func(componentType string) bool {
+		for _, t := range types {
+			if componentType == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hasTypePrefix returns a matcher for the component types starting with
+// prefix.
+func hasTypePrefix(prefix string) func(string) bool {
+	return func(componentType string) bool {
+		return strings.HasPrefix(componentType, prefix)
+	}
+}
+
+// hcl2Sources returns the bodies of the source blocks of the file whose
+// builder type is matched, including the source blocks of the builds that
+// override their settings.
+func hcl2Sources(f *hclwrite.File, match func(string) bool) []*hclwrite.Body {
+	var bodies []*hclwrite.Body
+	for _, block := range f.Body().Blocks() {
+		switch block.Type() {
+		case "source":
+			if labels := block.Labels(); len(labels) == 2 && match(labels[0]) {
+				bodies = append(bodies, block.Body())
+			}
+		case "build":
+			for _, inner := range block.Body().Blocks() {
+				if inner.Type() != "source" || len(inner.Labels()) != 1 {
+					continue
+				}
+				builderType, _, _ := strings.Cut(inner.Labels()[0], ".")
+				if match(builderType) {
+					bodies = append(bodies, inner.Body())
+				}
+			}
+		}
+	}
+	return bodies
+}
+
+// hcl2Provisioners returns the bodies of the provisioner blocks of the builds
+// of the file whose type is matched.
+func hcl2Provisioners(f *hclwrite.File, match func(string) bool) []*hclwrite.Body {
+	var bodies []*hclwrite.Body
+	for _, build := range buildBlocks(f) {
+		for _, block := range build.Body().Blocks() {
+			if block.Type() != "provisioner" && block.Type() != "error-cleanup-provisioner" {
+				continue
+			}
+			if labels := block.Labels(); len(labels) == 1 && match(labels[0]) {
+				bodies = append(bodies, block.Body())
+			}
+		}
+	}
+	return bodies
+}
+
+// hcl2PostProcessors returns the bodies of the post-processor blocks of the
+// builds of the file whose type is matched, including the ones in
+// post-processors blocks.
+func hcl2PostProcessors(f *hclwrite.File, match func(string) bool) []*hclwrite.Body {
+	var bodies []*hclwrite.Body
+	add := func(block *hclwrite.Block) {
+		if labels := block.Labels(); block.Type() == "post-processor" && len(labels) == 1 && match(labels[0]) {
+			bodies = append(bodies, block.Body())
+		}
+	}
+	for _, build := range buildBlocks(f) {
+		for _, block := range build.Body().Blocks() {
+			add(block)
+			if block.Type() == "post-processors" {
+				for _, inner := range block.Body().Blocks() {
+					add(inner)
+				}
+			}
+		}
+	}
+	return bodies
+}
+
+func buildBlocks(f *hclwrite.File) []*hclwrite.Block {
+	var builds []*hclwrite.Block
+	for _, block := range f.Body().Blocks() {
+		if block.Type() == "build" {
+			builds = append(builds, block)
+		}
+	}
+	return builds
+}
+
+// renameAttribute renames the from attribute of the body to to, keeping its
+// expression and comments. When the body already sets to, from is removed.
+func renameAttribute(body *hclwrite.Body, from, to string) {
+	if body.GetAttribute(from) == nil {
+		return
+	}
+	if body.GetAttribute(to) != nil {
+		body.RemoveAttribute(from)
+		return
+	}
+	body.RenameAttribute(from, to)
+}
+
+// expressionTokens returns the tokens of the expression of an attribute,
+// without the spaces before the expression.
+func expressionTokens(attr *hclwrite.Attribute) hclwrite.Tokens {
+	tokens := attr.Expr().BuildTokens(nil)
+	if len(tokens) > 0 {
+		first := *tokens[0]
+		first.SpacesBefore = 0
+		tokens[0] = &first
+	}
+	return tokens
+}
+
+// stringLiteral returns the value of the expression of an attribute when it
+// is a string without interpolations.
+func stringLiteral(attr *hclwrite.Attribute) (string, bool) {
+	tokens := expressionTokens(attr)
+	switch {
+	case len(tokens) == 2 && tokens[0].Type == hclsyntax.TokenOQuote && tokens[1].Type == hclsyntax.TokenCQuote:
+		return "", true
+	case len(tokens) == 3 && tokens[0].Type == hclsyntax.TokenOQuote && tokens[1].Type == hclsyntax.TokenQuotedLit && tokens[2].Type == hclsyntax.TokenCQuote:
+		expr, diags := hclsyntax.ParseExpression(tokens.Bytes(), "", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return "", false
+		}
+		val, diags := expr.Value(nil)
+		if diags.HasErrors() || val.Type() != cty.String {
+			return "", false
+		}
+		return val.AsString(), true
+	}
+	return "", false
+}
+
+// boolLiteral returns the value of the expression of an attribute when it is
+// true or false, quoted or not.
+func boolLiteral(attr *hclwrite.Attribute) (bool, bool) {
+	tokens := expressionTokens(attr)
+	if len(tokens) == 1 && tokens[0].Type == hclsyntax.TokenIdent {
+		switch string(tokens[0].Bytes) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	if s, ok := stringLiteral(attr); ok {
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// templateTokens returns the tokens of a string template concatenating the
+// parts. Parts that are strings are inlined, other expressions are
+// interpolated.
+func templateTokens(parts ...hclwrite.Tokens) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)}}
+	for _, part := range parts {
+		if isQuotedTemplate(part) {
+			tokens = append(tokens, part[1:len(part)-1]...)
+			continue
+		}
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateInterp, Bytes: []byte(`${`)})
+		tokens = append(tokens, part...)
+		tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateSeqEnd, Bytes: []byte(`}`)})
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)})
+}
+
+// stringTokens returns the tokens of a quoted string.
+func stringTokens(s string) hclwrite.Tokens {
+	return hclwrite.TokensForValue(cty.StringVal(s))
+}
+
+// isQuotedTemplate tells whether the tokens are a single quoted template,
+// like "${var.a}-suffix".
+func isQuotedTemplate(tokens hclwrite.Tokens) bool {
+	if len(tokens) < 2 || tokens[0].Type != hclsyntax.TokenOQuote || tokens[len(tokens)-1].Type != hclsyntax.TokenCQuote {
+		return false
+	}
+	for _, t := range tokens[1 : len(tokens)-1] {
+		if t.Type == hclsyntax.TokenOQuote || t.Type == hclsyntax.TokenCQuote {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package fix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFixHCL2(t *testing.T) {
+	tc := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "nothing to fix keeps formatting",
+			input: `source "null" "example" {
+  communicator =    "none"
+}
+`,
+			expected: `source "null" "example" {
+  communicator =    "none"
+}
+`,
+		},
+		{
+			name: "renamed source options keep comments",
+			input: `# builder
+source "amazon-ebs" "example" {
+  ssh_key_path = "id_rsa" # the key
+  ssh_disable_agent = true
+  ssh_wait_timeout = var.timeout
+  shutdown_behaviour = "terminate"
+  enhanced_networking = true
+}
+`,
+			expected: `# builder
+source "amazon-ebs" "example" {
+  ssh_private_key_file         = "id_rsa" # the key
+  ssh_disable_agent_forwarding = true
+  ssh_timeout                  = var.timeout
+  shutdown_behavior            = "terminate"
+  ena_support                  = true
+}
+`,
+		},
+		{
+			name: "new option already set",
+			input: `source "null" "example" {
+  ssh_key_path         = "old"
+  ssh_private_key_file = "new"
+}
+`,
+			expected: `source "null" "example" {
+  ssh_private_key_file = "new"
+}
+`,
+		},
+		{
+			name: "builder type filters",
+			input: `source "qemu" "example" {
+  shutdown_behaviour = "terminate"
+  ssh_host_port_min  = 2222
+  disk_size          = 10000
+}
+`,
+			expected: `source "qemu" "example" {
+  shutdown_behaviour = "terminate"
+  host_port_min      = 2222
+  disk_size          = "10000M"
+}
+`,
+		},
+		{
+			name: "build source blocks",
+			input: `build {
+  source "amazon-ebs.example" {
+    ssh_private_ip = "false"
+  }
+}
+`,
+			expected: `build {
+  source "amazon-ebs.example" {
+    ssh_interface = "public_ip"
+  }
+}
+`,
+		},
+		{
+			name: "iso checksum type",
+			input: `source "virtualbox-iso" "a" {
+  iso_checksum_type = "sha256"
+  iso_checksum      = var.checksum
+}
+
+source "virtualbox-iso" "b" {
+  iso_checksum_type = var.type
+  iso_checksum      = "abc"
+}
+
+source "virtualbox-iso" "c" {
+  iso_checksum_type = "none"
+  iso_checksum      = ""
+}
+
+source "virtualbox-iso" "d" {
+  iso_checksum_url = "${var.mirror}/SHA256SUMS"
+}
+
+source "virtualbox-iso" "e" {
+  iso_md5 = "deadbeef"
+}
+`,
+			expected: `source "virtualbox-iso" "a" {
+  iso_checksum = "sha256:${var.checksum}"
+}
+
+source "virtualbox-iso" "b" {
+  iso_checksum = "${var.type}:abc"
+}
+
+source "virtualbox-iso" "c" {
+  iso_checksum = "none"
+}
+
+source "virtualbox-iso" "d" {
+  iso_checksum = "file:${var.mirror}/SHA256SUMS"
+}
+
+source "virtualbox-iso" "e" {
+  iso_checksum = "md5:deadbeef"
+}
+`,
+		},
+		{
+			name: "provisioners and post-processors",
+			input: `build {
+  sources = ["source.null.example"]
+
+  provisioner "ansible-local" {
+    galaxycommand = "ansible-galaxy"
+  }
+
+  post-processor "manifest" {
+    filename = "manifest.json"
+  }
+
+  post-processors {
+    post-processor "docker-tag" {
+      tag = "1.0, latest"
+    }
+    post-processor "docker-tag" {
+      tag  = var.tag
+      tags = ["latest"]
+    }
+  }
+}
+`,
+			expected: `build {
+  sources = ["source.null.example"]
+
+  provisioner "ansible-local" {
+    galaxy_command = "ansible-galaxy"
+  }
+
+  post-processor "manifest" {
+    output = "manifest.json"
+  }
+
+  post-processors {
+    post-processor "docker-tag" {
+      tags = ["1.0", "latest"]
+    }
+    post-processor "docker-tag" {
+      tags = distinct(concat(["latest"], [var.tag]))
+    }
+  }
+}
+`,
+		},
+		{
+			name: "proxmox type",
+			input: `source "proxmox" "example" {
+}
+
+build {
+  sources = ["source.proxmox.example"]
+
+  provisioner "shell" {
+    only = ["proxmox.example"]
+  }
+}
+`,
+			expected: `source "proxmox-iso" "example" {
+}
+
+build {
+  sources = ["source.proxmox-iso.example"]
+
+  provisioner "shell" {
+    only = ["proxmox-iso.example"]
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := FixHCL2([]byte(tt.input), "test.pkr.hcl")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tt.expected, string(out)); diff != "" {
+				t.Errorf("unexpected output: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFixHCL2_invalid(t *testing.T) {
+	if _, err := FixHCL2([]byte(`source "null" {`), "test.pkr.hcl"); err == nil {
+		t.Fatal("expected a parsing error")
+	}
+}
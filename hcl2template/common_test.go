@@ -17,7 +17,6 @@ import (
 	"github.com/hashicorp/packer/builder/null"
 	dnull "github.com/hashicorp/packer/datasource/null"
 	. "github.com/hashicorp/packer/hcl2template/internal"
-	hcl2template "github.com/hashicorp/packer/hcl2template/internal"
 	"github.com/hashicorp/packer/packer"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -352,8 +351,12 @@ var cmpOpts = []cmp.Option{
 	cmpopts.IgnoreUnexported(
 		PackerConfig{},
 		Variable{},
+		VariableValidation{},
 		SourceBlock{},
 		DatasourceBlock{},
+		CommunicatorBlock{},
+		buildConditions{},
+		ProvisionerGroupBlock{},
 		ProvisionerBlock{},
 		PostProcessorBlock{},
 		packer.CoreBuild{},
@@ -380,7 +383,6 @@ var cmpOpts = []cmp.Option{
 	cmpopts.IgnoreFields(packer.CoreBuildPostProcessor{},
 		"HCLConfig",
 	),
-	cmpopts.IgnoreTypes(hcl2template.MockBuilder{}),
 	cmpopts.IgnoreTypes(HCL2Ref{}),
 	cmpopts.IgnoreTypes([]*LocalBlock{}),
 	cmpopts.IgnoreTypes([]hcl.Range{}),
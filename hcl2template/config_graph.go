@@ -0,0 +1,244 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/packer/packer"
+)
+
+// ConfigGraph returns the graph of the datasources and locals of the config,
+// and of its builds with their sources, provisioners and post-processors.
+// Edges are added for every reference from a block to a datasource, a local or
+// a communicator, and to an input variable when opts.IncludeVariables is set.
+func (cfg *PackerConfig) ConfigGraph(opts packer.ConfigGraphOptions) (*packer.ConfigGraph, hcl.Diagnostics) {
+	g := &configGraphBuilder{
+		graph: &packer.ConfigGraph{},
+		nodes: map[string]bool{},
+		edges: map[packer.ConfigGraphEdge]bool{},
+		refs:  []string{dataAccessor, localsAccessor, communicatorAccessor},
+	}
+	if opts.IncludeVariables {
+		g.refs = append(g.refs, inputVariablesAccessor)
+	}
+
+	// Nodes that can be referenced are added first, so that references
+	// can be checked against them.
+	if opts.IncludeVariables {
+		for _, name := range sortedKeys(cfg.InputVariables) {
+			g.addNode("var."+name, "variable", "var."+name)
+		}
+	}
+	for _, local := range cfg.LocalBlocks {
+		g.addNode("local."+local.LocalName, "local", "local."+local.LocalName)
+	}
+	var datasources []DatasourceBlock
+	for _, ds := range cfg.Datasources {
+		datasources = append(datasources, ds)
+	}
+	sort.Slice(datasources, func(i, j int) bool { return datasources[i].Name() < datasources[j].Name() })
+	for _, ds := range datasources {
+		g.addNode("data."+ds.Name(), "data", "data."+ds.Name())
+	}
+	var communicators []CommunicatorRef
+	for ref := range cfg.Communicators {
+		communicators = append(communicators, ref)
+	}
+	sort.Slice(communicators, func(i, j int) bool { return communicators[i].String() < communicators[j].String() })
+	for _, ref := range communicators {
+		g.addNode(ref.String(), "communicator", ref.String())
+	}
+	var sources []SourceRef
+	for ref := range cfg.Sources {
+		sources = append(sources, ref)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].String() < sources[j].String() })
+	for _, ref := range sources {
+		g.addNode("source."+ref.String(), "source", "source."+ref.String())
+	}
+
+	for _, local := range cfg.LocalBlocks {
+		g.addReferences("local."+local.LocalName, local.Expr.Variables())
+	}
+	for _, ds := range datasources {
+		if ds.block != nil {
+			g.addReferences("data."+ds.Name(), bodyTraversals(ds.block.Body))
+		}
+	}
+	for _, ref := range communicators {
+		g.addReferences(ref.String(), bodyTraversals(cfg.Communicators[ref].block.Body))
+	}
+	for _, ref := range sources {
+		if src := cfg.Sources[ref]; src.block != nil {
+			g.addReferences("source."+ref.String(), bodyTraversals(src.block.Body))
+		}
+	}
+
+	for i, build := range cfg.Builds {
+		buildID := fmt.Sprintf("build[%d]", i)
+		label := "build"
+		if build.Name != "" {
+			buildID = "build." + build.Name
+			label = fmt.Sprintf("build %q", build.Name)
+		}
+		g.addNode(buildID, "build", label)
+
+		for _, src := range build.Sources {
+			g.addEdge(buildID, "source."+src.SourceRef.String(), packer.ConfigGraphContains)
+			if src.Body == nil {
+				continue
+			}
+			// The body of a used source is merged with the one of its
+			// source block, whose references are already in the graph.
+			var sourceTraversals []hcl.Traversal
+			if def := cfg.Sources[src.SourceRef]; def.block != nil {
+				sourceTraversals = bodyTraversals(def.block.Body)
+			}
+			g.addReferences(buildID, excludeTraversals(bodyTraversals(src.Body), sourceTraversals))
+		}
+
+		previous := buildID
+		for j, pb := range build.ProvisionerBlocks {
+			id := fmt.Sprintf("%s.provisioner[%d]", buildID, j)
+			g.addNode(id, "provisioner", blockLabel(buildProvisionerLabel, pb.PType, pb.PName))
+			g.addEdge(buildID, id, packer.ConfigGraphContains)
+			if previous != buildID {
+				g.addEdge(previous, id, packer.ConfigGraphNext)
+			}
+			previous = id
+			g.addReferences(id, whenTraversals(pb.Rest, pb.When))
+		}
+
+		if pb := build.ErrorCleanupProvisionerBlock; pb != nil {
+			id := buildID + ".error-cleanup-provisioner"
+			g.addNode(id, "error-cleanup-provisioner", blockLabel(buildErrorCleanupProvisionerLabel, pb.PType, pb.PName))
+			g.addEdge(buildID, id, packer.ConfigGraphContains)
+			g.addReferences(id, whenTraversals(pb.Rest, pb.When))
+		}
+
+		for j, ppList := range build.PostProcessorsLists {
+			previous := ""
+			for k, ppb := range ppList {
+				id := fmt.Sprintf("%s.post-processor[%d][%d]", buildID, j, k)
+				g.addNode(id, "post-processor", blockLabel(buildPostProcessorLabel, ppb.PType, ppb.PName))
+				if previous == "" {
+					g.addEdge(buildID, id, packer.ConfigGraphContains)
+				} else {
+					g.addEdge(previous, id, packer.ConfigGraphNext)
+				}
+				previous = id
+				g.addReferences(id, whenTraversals(ppb.Rest, ppb.When))
+			}
+		}
+	}
+
+	return g.graph, nil
+}
+
+type configGraphBuilder struct {
+	graph *packer.ConfigGraph
+	nodes map[string]bool
+	edges map[packer.ConfigGraphEdge]bool
+	// refs are the root names of the traversals that become edges.
+	refs []string
+}
+
+func (g *configGraphBuilder) addNode(id, nodeType, label string) {
+	if g.nodes[id] {
+		return
+	}
+	g.nodes[id] = true
+	g.graph.Nodes = append(g.graph.Nodes, packer.ConfigGraphNode{ID: id, Type: nodeType, Label: label})
+}
+
+func (g *configGraphBuilder) addEdge(from, to, kind string) {
+	e := packer.ConfigGraphEdge{From: from, To: to, Kind: kind}
+	if !g.nodes[from] || !g.nodes[to] || g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.graph.Edges = append(g.graph.Edges, e)
+}
+
+// addReferences adds a depends_on edge from the node to every node referenced
+// by the traversals, in a stable order.
+func (g *configGraphBuilder) addReferences(from string, traversals []hcl.Traversal) {
+	var targets []string
+	for _, t := range FilterTraversalsByType(traversals, g.refs...) {
+		if target, ok := traversalNodeID(t); ok {
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		g.addEdge(from, target, packer.ConfigGraphDependsOn)
+	}
+}
+
+// traversalNodeID returns the ID of the variable, local, datasource or
+// communicator node referenced by a traversal.
+func traversalNodeID(t hcl.Traversal) (string, bool) {
+	length := 2
+	if t.RootName() == dataAccessor || t.RootName() == communicatorAccessor {
+		length = 3
+	}
+	if len(t) < length {
+		return "", false
+	}
+	id := t.RootName()
+	for _, step := range t[1:length] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			return "", false
+		}
+		id += "." + attr.Name
+	}
+	return id, true
+}
+
+// excludeTraversals returns the traversals that are not in exclude, comparing
+// them by their location.
+func excludeTraversals(traversals, exclude []hcl.Traversal) []hcl.Traversal {
+	excluded := map[hcl.Range]bool{}
+	for _, t := range exclude {
+		excluded[t.SourceRange()] = true
+	}
+	var res []hcl.Traversal
+	for _, t := range traversals {
+		if !excluded[t.SourceRange()] {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// whenTraversals returns the traversals of the body of a provisioner or
+// post-processor block, along with the ones of its when argument, which is
+// not part of the body.
+func whenTraversals(body hcl.Body, when hcl.Expression) []hcl.Traversal {
+	traversals := bodyTraversals(body)
+	if when != nil {
+		traversals = append(traversals, when.Variables()...)
+	}
+	return traversals
+}
+
+func blockLabel(blockType, pluginType, name string) string {
+	if name != "" {
+		return fmt.Sprintf("%s %q %q", blockType, pluginType, name)
+	}
+	return fmt.Sprintf("%s %q", blockType, pluginType)
+}
+
+func sortedKeys(vars Variables) []string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
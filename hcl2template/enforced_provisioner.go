@@ -68,9 +68,11 @@ func (cfg *PackerConfig) GetCoreBuildProvisionerFromBlock(pb *ProvisionerBlock,
 
 	// Wrap provisioner with any special behavior (pause, timeout, retry)
 	wrappedProvisioner := packer.WrapProvisionerWithOptions(hclProvisioner, packer.ProvisionerWrapOptions{
-		PauseBefore: pb.PauseBefore,
-		Timeout:     pb.Timeout,
-		MaxRetries:  pb.MaxRetries,
+		PauseBefore:  pb.PauseBefore,
+		Timeout:      pb.Timeout,
+		MaxRetries:   pb.MaxRetries,
+		RetryBackoff: pb.RetryBackoff,
+		RetryOn:      pb.RetryOn,
 	})
 
 	return packer.CoreBuildProvisioner{
@@ -0,0 +1,112 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/packer/fix"
+)
+
+// HCL2Fixer runs the fixers of the fix package that support HCL2 on
+// configuration files.
+type HCL2Fixer struct {
+	ShowDiff, Write bool
+	Output          io.Writer
+}
+
+// Fix fixes the HCL2 configuration files in paths, and returns the number of
+// files that needed fixing. The name of these files is written to Output,
+// followed by the diff of the fixes when ShowDiff is true, and the files are
+// overwritten when Write is true.
+//
+// Paths can be directories or files; JSON configuration files are ignored.
+func (f *HCL2Fixer) Fix(paths []string) (int, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	fixed := 0
+
+	if f.Output == nil {
+		f.Output = os.Stdout
+	}
+
+	for _, path := range paths {
+		var filenames []string
+		if s, err := os.Stat(path); err == nil && s.IsDir() {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Cannot read hcl directory",
+					Detail:   err.Error(),
+				})
+				return fixed, diags
+			}
+			for _, entry := range entries {
+				name := entry.Name()
+				if !entry.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, hcl2FileExt) {
+					filenames = append(filenames, filepath.Join(path, name))
+				}
+			}
+		} else if strings.HasSuffix(path, hcl2FileExt) {
+			filenames = append(filenames, path)
+		}
+
+		for _, filename := range filenames {
+			changed, err := f.fixFile(filename)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("encountered an error while fixing %s", filename),
+					Detail:   err.Error(),
+				})
+				continue
+			}
+			if changed {
+				fixed++
+			}
+		}
+	}
+
+	return fixed, diags
+}
+
+// fixFile fixes a configuration file and tells whether it needed fixing.
+func (f *HCL2Fixer) fixFile(filename string) (bool, error) {
+	inSrc, err := os.ReadFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %s", filename, err)
+	}
+
+	outSrc, err := fix.FixHCL2(inSrc, filename)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(inSrc, outSrc) {
+		return false, nil
+	}
+
+	_, _ = fmt.Fprintf(f.Output, "%s\n", filename)
+
+	if f.Write {
+		if err := os.WriteFile(filename, outSrc, 0644); err != nil {
+			return true, err
+		}
+	}
+
+	if f.ShowDiff {
+		diff, err := bytesDiff(inSrc, outSrc, filename)
+		if err != nil {
+			return true, fmt.Errorf("failed to generate diff for %s: %s", filename, err)
+		}
+		_, _ = f.Output.Write(diff)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,46 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestHCL2Fixer_Fix_ShowDiff(t *testing.T) {
+	if _, err := exec.LookPath("diff"); err != nil {
+		t.Skip("Skipping test because diff is not in the executable PATH")
+	}
+
+	var buf bytes.Buffer
+	f := HCL2Fixer{
+		Output:   &buf,
+		ShowDiff: true,
+	}
+
+	fixed, diags := f.Fix([]string{"testdata/fix"})
+	if diags.HasErrors() {
+		t.Fatalf("the call to Fix failed unexpectedly %s", diags.Error())
+	}
+	if fixed != 1 {
+		t.Fatalf("expected 1 file to be fixed, got %d", fixed)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "formatted.pkr.hcl") {
+		t.Errorf("files without deprecated options should not be listed, got %s", out)
+	}
+	for _, expected := range []string{
+		"testdata/fix/deprecated.pkr.hcl\n",
+		"+++ new/testdata/fix/deprecated.pkr.hcl\n",
+		"-  ssh_key_path     = \"id_rsa\"\n",
+		"+  ssh_private_key_file = \"id_rsa\"\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected the output to contain %q, got %s", expected, out)
+		}
+	}
+}
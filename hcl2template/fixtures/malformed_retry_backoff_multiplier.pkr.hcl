@@ -0,0 +1,9 @@
+provisioner "shell-local" {
+  inline      = ["echo 'hi'"]
+  max_retries = 3
+
+  retry_backoff {
+    initial_delay = "5s"
+    multiplier    = 0.5
+  }
+}
@@ -0,0 +1,5 @@
+provisioner "shell-local" {
+  inline      = ["echo 'hi'"]
+  max_retries = 3
+  retry_on    = ["mirror (unavailable"]
+}
@@ -0,0 +1,12 @@
+provisioner "shell-local" {
+  inline      = ["echo 'hi'"]
+  max_retries = 3
+  retry_on    = ["connection reset", "^mirror .* unavailable$"]
+
+  retry_backoff {
+    initial_delay = "5s"
+    multiplier    = 2
+    max_delay     = "1m"
+    jitter        = 0.2
+  }
+}
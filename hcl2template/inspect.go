@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// sensitiveValue replaces the values of sensitive variables and locals in the
+// JSON inspection.
+var sensitiveValue = json.RawMessage(`"<sensitive>"`)
+
+// inspection is the JSON output of `packer inspect -format=json`.
+type inspection struct {
+	Variables   []inspectedVariable   `json:"variables"`
+	Locals      []inspectedLocal      `json:"locals"`
+	Datasources []inspectedDatasource `json:"datasources"`
+	Sources     []inspectedSource     `json:"sources"`
+	Builds      []inspectedBuild      `json:"builds"`
+}
+
+type inspectedVariable struct {
+	Name string `json:"name"`
+	// Type is the type constraint of the variable, as written in HCL.
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Default is null when the variable has no default value.
+	Default json.RawMessage `json:"default"`
+	// Value is the value used for the build, it is null when unknown.
+	Value       json.RawMessage `json:"value"`
+	Required    bool            `json:"required"`
+	Sensitive   bool            `json:"sensitive"`
+	Validations int             `json:"validations"`
+}
+
+type inspectedLocal struct {
+	Name string `json:"name"`
+	// Value is null when the local could not be evaluated.
+	Value     json.RawMessage `json:"value"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+type inspectedDatasource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type inspectedSource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// LocalName is the name given to the source in a build block.
+	LocalName string `json:"local_name,omitempty"`
+}
+
+type inspectedBuild struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Sources     []inspectedSource `json:"sources"`
+
+	Provisioners            []inspectedProvisioner `json:"provisioners"`
+	ErrorCleanupProvisioner *inspectedProvisioner  `json:"error_cleanup_provisioner"`
+	// PostProcessors are the sequences of post-processors of the build.
+	PostProcessors [][]inspectedPostProcessor `json:"post_processors"`
+}
+
+type inspectedProvisioner struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	OnlyExcept
+}
+
+type inspectedPostProcessor struct {
+	Type              string `json:"type"`
+	Name              string `json:"name,omitempty"`
+	KeepInputArtifact *bool  `json:"keep_input_artifact,omitempty"`
+	OnlyExcept
+}
+
+// inspection returns the components of the config in a machine readable form.
+// Everything is sorted by name, except the blocks of the builds that are kept
+// in the order they run.
+func (cfg *PackerConfig) inspection() inspection {
+	out := inspection{
+		Variables:   []inspectedVariable{},
+		Locals:      []inspectedLocal{},
+		Datasources: []inspectedDatasource{},
+		Sources:     []inspectedSource{},
+		Builds:      []inspectedBuild{},
+	}
+
+	keys := cfg.InputVariables.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		v := cfg.InputVariables[key]
+		iv := inspectedVariable{
+			Name:        v.Name,
+			Type:        typeexpr.TypeString(v.Type),
+			Description: v.Description,
+			Default:     json.RawMessage("null"),
+			Value:       inspectedValue(v.Value(), v.Sensitive),
+			Required:    true,
+			Sensitive:   v.Sensitive,
+			Validations: len(v.Validations),
+		}
+		for _, val := range v.Values {
+			if val.From == "default" {
+				iv.Default = inspectedValue(val.Value, v.Sensitive)
+				iv.Required = false
+			}
+		}
+		out.Variables = append(out.Variables, iv)
+	}
+
+	for _, local := range cfg.LocalBlocks {
+		value := cty.DynamicVal
+		if v, found := cfg.LocalVariables[local.LocalName]; found {
+			value = v.Value()
+		}
+		out.Locals = append(out.Locals, inspectedLocal{
+			Name:      local.LocalName,
+			Value:     inspectedValue(value, local.Sensitive),
+			Sensitive: local.Sensitive,
+		})
+	}
+	sort.Slice(out.Locals, func(i, j int) bool { return out.Locals[i].Name < out.Locals[j].Name })
+
+	for ref := range cfg.Datasources {
+		out.Datasources = append(out.Datasources, inspectedDatasource{Type: ref.Type, Name: ref.Name})
+	}
+	sort.Slice(out.Datasources, func(i, j int) bool {
+		a, b := out.Datasources[i], out.Datasources[j]
+		return a.Type < b.Type || a.Type == b.Type && a.Name < b.Name
+	})
+
+	for ref := range cfg.Sources {
+		out.Sources = append(out.Sources, inspectedSource{Type: ref.Type, Name: ref.Name})
+	}
+	sort.Slice(out.Sources, func(i, j int) bool {
+		a, b := out.Sources[i], out.Sources[j]
+		return a.Type < b.Type || a.Type == b.Type && a.Name < b.Name
+	})
+
+	for _, build := range cfg.Builds {
+		ib := inspectedBuild{
+			Name:           build.Name,
+			Description:    build.Description,
+			Sources:        []inspectedSource{},
+			Provisioners:   []inspectedProvisioner{},
+			PostProcessors: [][]inspectedPostProcessor{},
+		}
+		for _, src := range build.Sources {
+			ib.Sources = append(ib.Sources, inspectedSource{
+				Type:      src.Type,
+				Name:      src.Name,
+				LocalName: src.LocalName,
+			})
+		}
+		for _, pb := range build.ProvisionerBlocks {
+			ib.Provisioners = append(ib.Provisioners, inspectedProvisioner{
+				Type:       pb.PType,
+				Name:       pb.PName,
+				OnlyExcept: pb.OnlyExcept,
+			})
+		}
+		if pb := build.ErrorCleanupProvisionerBlock; pb != nil {
+			ib.ErrorCleanupProvisioner = &inspectedProvisioner{
+				Type:       pb.PType,
+				Name:       pb.PName,
+				OnlyExcept: pb.OnlyExcept,
+			}
+		}
+		for _, ppList := range build.PostProcessorsLists {
+			var list []inspectedPostProcessor
+			for _, ppb := range ppList {
+				list = append(list, inspectedPostProcessor{
+					Type:              ppb.PType,
+					Name:              ppb.PName,
+					KeepInputArtifact: ppb.KeepInputArtifact,
+					OnlyExcept:        ppb.OnlyExcept,
+				})
+			}
+			ib.PostProcessors = append(ib.PostProcessors, list)
+		}
+		out.Builds = append(out.Builds, ib)
+	}
+
+	return out
+}
+
+// inspectedValue returns the JSON encoding of a value, or null if it is not
+// known. Sensitive values are redacted.
+func inspectedValue(value cty.Value, sensitive bool) json.RawMessage {
+	if !value.IsWhollyKnown() || value.IsNull() {
+		return json.RawMessage("null")
+	}
+	if sensitive {
+		return sensitiveValue
+	}
+	b, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return b
+}
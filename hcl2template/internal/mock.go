@@ -40,6 +40,7 @@ type MockTag struct {
 
 type MockConfig struct {
 	NotSquashed      string `mapstructure:"not_squashed"`
+	Communicator     string `mapstructure:"communicator"`
 	NestedMockConfig `mapstructure:",squash"`
 	Nested           NestedMockConfig   `mapstructure:"nested"`
 	NestedSlice      []NestedMockConfig `mapstructure:"nested_slice"`
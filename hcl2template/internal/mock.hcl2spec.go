@@ -11,6 +11,7 @@ import (
 // Where the contents of a field with a `mapstructure:,squash` tag are bubbled up.
 type FlatMockConfig struct {
 	NotSquashed          *string                `mapstructure:"not_squashed" cty:"not_squashed" hcl:"not_squashed"`
+	Communicator         *string                `mapstructure:"communicator" cty:"communicator" hcl:"communicator"`
 	String               *string                `mapstructure:"string" cty:"string" hcl:"string"`
 	Int                  *int                   `mapstructure:"int" cty:"int" hcl:"int"`
 	Int64                *int64                 `mapstructure:"int64" cty:"int64" hcl:"int64"`
@@ -41,6 +42,7 @@ func (*MockConfig) FlatMapstructure() interface{ HCL2Spec() map[string]hcldec.Sp
 func (*FlatMockConfig) HCL2Spec() map[string]hcldec.Spec {
 	s := map[string]hcldec.Spec{
 		"not_squashed":            &hcldec.AttrSpec{Name: "not_squashed", Type: cty.String, Required: false},
+		"communicator":            &hcldec.AttrSpec{Name: "communicator", Type: cty.String, Required: false},
 		"string":                  &hcldec.AttrSpec{Name: "string", Type: cty.String, Required: false},
 		"int":                     &hcldec.AttrSpec{Name: "int", Type: cty.Number, Required: false},
 		"int64":                   &hcldec.AttrSpec{Name: "int64", Type: cty.Number, Required: false},
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
@@ -44,6 +45,7 @@ var configSchema = &hcl.BodySchema{
 		{Type: buildLabel},
 		{Type: hcpPackerRegistryLabel},
 		{Type: communicatorLabel, LabelNames: []string{"type", "name"}},
+		{Type: provisionerGroupLabel, LabelNames: []string{"name"}},
 	},
 }
 
@@ -480,6 +482,11 @@ func (cfg *PackerConfig) buildPrereqsDAG() (*dag.AcyclicGraph, error) {
 	return &retGraph, err
 }
 
+// buildPrereqsParallelism is the maximum number of datasources and locals
+// evaluated at the same time. Datasources mostly wait on the network, so this
+// is not bound to the number of CPUs.
+const buildPrereqsParallelism = 10
+
 func (cfg *PackerConfig) evaluateBuildPrereqs(skipDatasources bool) hcl.Diagnostics {
 	diags := cfg.detectBuildPrereqDependencies()
 	if diags.HasErrors() {
@@ -495,13 +502,20 @@ func (cfg *PackerConfig) evaluateBuildPrereqs(skipDatasources bool) hcl.Diagnost
 		})
 	}
 
+	// Vertices are evaluated concurrently, lock guards the datasources and
+	// locals of cfg.
+	var lock sync.Mutex
+
 	walkFunc := func(v dag.Vertex) hcl.Diagnostics {
 		var diags hcl.Diagnostics
 
 		switch bl := v.(type) {
 		case *DatasourceBlock:
-			diags = cfg.evaluateDatasource(*bl, skipDatasources)
+			diags = cfg.evaluateDatasource(*bl, skipDatasources, &lock)
 		case *LocalBlock:
+			lock.Lock()
+			defer lock.Unlock()
+
 			var val *Variable
 			if cfg.LocalVariables == nil {
 				cfg.LocalVariables = make(Variables)
@@ -533,19 +547,16 @@ func (cfg *PackerConfig) evaluateBuildPrereqs(skipDatasources bool) hcl.Diagnost
 		return nil
 	}
 
-	for _, vtx := range graph.ReverseTopologicalOrder() {
-		vtxDiags := walkFunc(vtx)
-		if vtxDiags.HasErrors() {
-			diags = diags.Extend(vtxDiags)
-			return diags
-		}
+	if diags := graph.ConcurrentWalk(buildPrereqsParallelism, walkFunc); diags.HasErrors() {
+		return diags
 	}
 
 	return nil
 }
 
 func (cfg *PackerConfig) Initialize(opts packer.InitializeOptions) hcl.Diagnostics {
-	diags := cfg.InputVariables.ValidateValues()
+	cfg.envMetadata = opts.EnvMetadata
+	diags := cfg.validateInputVariables(false)
 
 	if opts.UseSequential {
 		diags = diags.Extend(cfg.evaluateDatasources(opts.SkipDatasourcesExecution))
@@ -554,9 +565,17 @@ func (cfg *PackerConfig) Initialize(opts packer.InitializeOptions) hcl.Diagnosti
 		diags = diags.Extend(cfg.evaluateBuildPrereqs(opts.SkipDatasourcesExecution))
 	}
 
+	// Validation rules referring to local values can only be evaluated now.
+	diags = diags.Extend(cfg.validateInputVariables(true))
+
 	filterVarsFromLogs(cfg.InputVariables)
 	filterVarsFromLogs(cfg.LocalVariables)
 
+	// provisioner groups are expanded in the builds that include them
+	for _, file := range cfg.files {
+		diags = append(diags, cfg.parser.decodeProvisionerGroups(file, cfg)...)
+	}
+
 	// parse the actual content // rest
 	for _, file := range cfg.files {
 		diags = append(diags, cfg.parser.parseConfig(file, cfg)...)
@@ -621,6 +640,32 @@ func (p *Parser) parseConfig(f *hcl.File, cfg *PackerConfig) hcl.Diagnostics {
 			}
 			cfg.Sources[ref] = source
 
+		case communicatorLabel:
+			communicator, moreDiags := p.decodeCommunicator(block)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+
+			ref := communicator.Ref()
+			if existing, found := cfg.Communicators[ref]; found {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate " + communicatorLabel + " block",
+					Detail: fmt.Sprintf("This "+communicatorLabel+" block has the "+
+						"same type and name as a previous block declared "+
+						"at %s. Each "+communicatorLabel+" must have a unique name per type.",
+						existing.block.DefRange.Ptr()),
+					Subject: communicator.block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			if cfg.Communicators == nil {
+				cfg.Communicators = map[CommunicatorRef]*CommunicatorBlock{}
+			}
+			cfg.Communicators[ref] = communicator
+
 		case buildLabel:
 			build, moreDiags := p.decodeBuildConfig(block, cfg)
 			diags = append(diags, moreDiags...)
@@ -7,9 +7,11 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"path/filepath"
 	"runtime"
 	"strings"
 
+	goversion "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/packer-plugin-sdk/didyoumean"
 	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
@@ -79,9 +81,29 @@ func (cfg *PackerConfig) DetectPluginBinaries() hcl.Diagnostics {
 		return diags
 	}
 
+	lockPath := filepath.Join(cfg.Basedir, plugingetter.LockFileName)
+	lock, err := plugingetter.ReadLockFile(lockPath)
+	if err != nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Failed to read %s", lockPath),
+			Detail:   err.Error(),
+		})
+	}
+
 	uninstalledPlugins := map[string]string{}
 
 	for _, pluginRequirement := range pluginReqs {
+		var locked *plugingetter.LockedPlugin
+		if lock.Exists() {
+			var moreDiags hcl.Diagnostics
+			locked, moreDiags = applyLockedVersion(pluginRequirement, lock, lockPath)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+		}
+
 		sortedInstalls, err := pluginRequirement.ListInstallations(opts)
 		if err != nil {
 			diags = append(diags, &hcl.Diagnostic{
@@ -97,6 +119,16 @@ func (cfg *PackerConfig) DetectPluginBinaries() hcl.Diagnostics {
 		}
 		log.Printf("[TRACE] Found the following %q installations: %v", pluginRequirement.Identifier, sortedInstalls)
 		install := sortedInstalls[len(sortedInstalls)-1]
+		if locked != nil {
+			if err := locked.Verify(install, opts.BinaryInstallationOptions); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Plugin %s does not match the lock file", pluginRequirement.Identifier),
+					Detail:   err.Error() + "\n\nRun packer init to reinstall the locked version.",
+				})
+				continue
+			}
+		}
 		err = cfg.parser.PluginConfig.DiscoverMultiPlugin(pluginRequirement.Accessor, install.BinaryPath)
 		if err != nil {
 			diags = append(diags, &hcl.Diagnostic{
@@ -123,7 +155,7 @@ func (cfg *PackerConfig) DetectPluginBinaries() hcl.Diagnostics {
 	}
 
 	// Do a second pass to discover the remaining installed plugins
-	err := cfg.parser.PluginConfig.Discover()
+	err = cfg.parser.PluginConfig.Discover()
 	if err != nil {
 		return (hcl.Diagnostics{}).Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -135,6 +167,40 @@ func (cfg *PackerConfig) DetectPluginBinaries() hcl.Diagnostics {
 	return diags
 }
 
+// applyLockedVersion restricts the version constraints of a requirement to the
+// version recorded in the lock file.
+func applyLockedVersion(req *plugingetter.Requirement, lock *plugingetter.LockFile, lockPath string) (*plugingetter.LockedPlugin, hcl.Diagnostics) {
+	locked, ok := lock.Plugins[req.Identifier.String()]
+	if !ok {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Plugin %s is missing from the lock file", req.Identifier),
+			Detail:   fmt.Sprintf("%s has no entry for this plugin, run packer init to update it.", lockPath),
+		}}
+	}
+
+	lockedConstraints, err := locked.VersionConstraints()
+	if err != nil {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid locked version for plugin %s", req.Identifier),
+			Detail:   err.Error(),
+		}}
+	}
+
+	if v, err := goversion.NewVersion(locked.Version); err != nil || !req.VersionConstraints.Check(v) {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Locked version of plugin %s does not match the required version", req.Identifier),
+			Detail: fmt.Sprintf("%s locks version %s, which does not satisfy %q. "+
+				"Run packer init -upgrade to select a new version.", lockPath, locked.Version, req.VersionConstraints),
+		}}
+	}
+
+	req.VersionConstraints = lockedConstraints
+	return locked, nil
+}
+
 func (cfg *PackerConfig) initializeBlocks() hcl.Diagnostics {
 	// verify that all used plugins do exist
 	var diags hcl.Diagnostics
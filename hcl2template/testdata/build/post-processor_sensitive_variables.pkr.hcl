@@ -0,0 +1,35 @@
+variable "token" {
+  type      = string
+  default   = "s3cr3t"
+  sensitive = true
+}
+
+variable "credentials" {
+  type = object({
+    user = string
+    key  = string
+  })
+  default = {
+    user = "packer"
+    key  = "k3y"
+  }
+  sensitive = true
+}
+
+variable "output" {
+  type = string
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["source.null.test"]
+
+  post-processor "manifest" {
+    output                 = var.output
+    include_generated_data = true
+    include_variables      = true
+  }
+}
@@ -0,0 +1,39 @@
+
+variable "environment" {
+  type    = string
+  default = "dev"
+}
+
+locals {
+  production = var.environment == "production"
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
+
+source "amazon-ebs" "ubuntu-1604" {
+}
+
+build {
+  sources = [
+    "source.virtualbox-iso.ubuntu-1204",
+    "source.amazon-ebs.ubuntu-1604",
+  ]
+
+  provisioner "shell" {
+    name = "hardening"
+    when = local.production
+  }
+  provisioner "file" {
+    name = "aws-only"
+    when = source.type == "amazon-ebs"
+  }
+
+  error-cleanup-provisioner "shell" {
+    when = local.production
+  }
+
+  post-processor "manifest" {
+    when = var.environment != "dev"
+  }
+}
@@ -0,0 +1,11 @@
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
+
+build {
+  sources = ["source.virtualbox-iso.ubuntu-1204"]
+
+  provisioner "shell" {
+    when = "sometimes"
+  }
+}
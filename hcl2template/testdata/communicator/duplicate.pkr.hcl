@@ -0,0 +1,8 @@
+
+communicator "ssh" "bastion" {
+  ssh_username = "packer"
+}
+
+communicator "ssh" "bastion" {
+  ssh_username = "admin"
+}
@@ -0,0 +1,4 @@
+
+communicator "telnet" "bastion" {
+  telnet_username = "packer"
+}
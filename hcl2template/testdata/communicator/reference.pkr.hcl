@@ -0,0 +1,30 @@
+variable "username" {
+  default = "packer"
+}
+
+communicator "ssh" "bastion" {
+  string   = var.username
+  int      = 42
+  duration = "10m"
+
+  nested {
+    string = "bastion"
+  }
+}
+
+source "virtualbox-iso" "web" {
+  communicator = communicator.ssh.bastion
+}
+
+source "virtualbox-iso" "db" {
+  communicator = communicator.ssh.bastion
+  duration     = "5m"
+
+  nested {
+    int = 1
+  }
+}
+
+build {
+  sources = ["source.virtualbox-iso.web", "source.virtualbox-iso.db"]
+}
@@ -0,0 +1,11 @@
+communicator "ssh" "bastion" {
+  string = "packer"
+}
+
+source "virtualbox-iso" "web" {
+  communicator = communicator.ssh.jump
+}
+
+build {
+  sources = ["source.virtualbox-iso.web"]
+}
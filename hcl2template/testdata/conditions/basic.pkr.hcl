@@ -0,0 +1,32 @@
+
+variable "disk_size" {
+  type    = number
+  default = 20
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  name = "conditions"
+
+  precondition {
+    condition     = var.disk_size >= 10
+    error_message = "The disk_size value must be at least 10, got ${var.disk_size}."
+  }
+
+  postcondition {
+    condition     = length(artifact.files) > 0
+    error_message = "The build must produce files."
+  }
+
+  source "null.test" {
+    name = "local"
+
+    postcondition {
+      condition     = startswith(artifact.id, "ami-") && build.ID == artifact.id
+      error_message = "The artifact ${artifact.id} of ${source.name} must be an AMI."
+    }
+  }
+}
@@ -0,0 +1,13 @@
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["null.test"]
+
+  precondition {
+    condition     = artifact.id != ""
+    error_message = "The build must produce an artifact."
+  }
+}
@@ -0,0 +1,16 @@
+source "null" "example" {
+  communicator = "ssh"
+  ssh_host     = "127.0.0.1"
+  ssh_username = "packer"
+  # Deprecated options
+  ssh_key_path     = "id_rsa"
+  ssh_wait_timeout = "10m"
+}
+
+build {
+  sources = ["source.null.example"]
+
+  post-processor "manifest" {
+    filename = "manifest.json"
+  }
+}
@@ -0,0 +1,34 @@
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
+
+source "amazon-ebs" "ubuntu-1604" {
+}
+
+build {
+  name    = "web"
+  sources = ["source.virtualbox-iso.ubuntu-1204"]
+
+  provisioner "shell" {
+    name = "first"
+  }
+
+  provisioner_group "baseline" {
+  }
+
+  provisioner "shell" {
+    name = "last"
+  }
+}
+
+build {
+  name    = "db"
+  sources = ["source.amazon-ebs.ubuntu-1604"]
+
+  provisioner_group "baseline" {
+    parameters = {
+      ssh_port = 2222
+      upload   = false
+    }
+  }
+}
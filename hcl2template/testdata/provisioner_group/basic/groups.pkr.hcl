@@ -0,0 +1,19 @@
+
+provisioner_group "baseline" {
+  parameters = {
+    ssh_port = 22
+    user     = "packer"
+    upload   = true
+  }
+
+  provisioner "shell" {
+    name   = "harden-${group.parameters.user}"
+    string = "harden --ssh-port ${group.parameters.ssh_port}"
+  }
+
+  provisioner "file" {
+    name   = "upload"
+    when   = group.parameters.upload
+    string = "${source.name}:${group.name}"
+  }
+}
@@ -0,0 +1,10 @@
+
+provisioner_group "baseline" {
+  provisioner "shell" {
+  }
+}
+
+provisioner_group "baseline" {
+  provisioner "file" {
+  }
+}
@@ -0,0 +1,15 @@
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
+
+provisioner_group "baseline" {
+  provisioner "shell" {
+  }
+}
+
+build {
+  sources = ["source.virtualbox-iso.ubuntu-1204"]
+
+  provisioner_group "hardening" {
+  }
+}
@@ -0,0 +1,33 @@
+
+variable "images" {
+  type = map(object({
+    os     = string
+    region = string
+  }))
+  default = {
+    focal = { os = "ubuntu-20.04", region = "us-east-1" }
+    jammy = { os = "ubuntu-22.04", region = "eu-west-1" }
+  }
+}
+
+source "virtualbox-iso" "base" {
+  int = 42
+}
+
+build {
+  source "virtualbox-iso.base" {
+    for_each = var.images
+
+    name   = "ubuntu-${each.key}"
+    string = each.value.os
+  }
+
+  source "virtualbox-iso.base" {
+    for_each = convert(["alpine", "debian"], set(string))
+  }
+
+  provisioner "shell" {
+    only   = ["virtualbox-iso.ubuntu-jammy", "virtualbox-iso.base-debian"]
+    string = "${source.name}"
+  }
+}
@@ -0,0 +1,10 @@
+
+source "virtualbox-iso" "base" {
+}
+
+build {
+  source "virtualbox-iso.base" {
+    for_each = convert(["alpine", "debian"], set(string))
+    name     = "static"
+  }
+}
@@ -0,0 +1,9 @@
+
+source "virtualbox-iso" "base" {
+}
+
+build {
+  source "virtualbox-iso.base" {
+    for_each = ["alpine", "debian"]
+  }
+}
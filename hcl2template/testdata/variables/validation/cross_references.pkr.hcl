@@ -0,0 +1,44 @@
+
+variable "base_image_size" {
+  type    = number
+  default = 10
+  validation {
+    condition     = var.base_image_size > 0
+    error_message = "The base_image_size value must be positive."
+  }
+}
+
+variable "disk_size" {
+  type    = number
+  default = 20
+  validation {
+    condition     = var.disk_size > var.base_image_size
+    error_message = "The disk_size value must be larger than base_image_size."
+  }
+}
+
+variable "region" {
+  type    = string
+  default = "eu-west-1"
+  validation {
+    condition     = contains(local.allowed_regions, var.region)
+    error_message = "The region value must be an allowed region."
+  }
+  validation {
+    condition     = !contains(local.blocked_regions, var.region)
+    error_message = "The region value must not be a China region."
+  }
+}
+
+locals {
+  allowed_regions = ["eu-west-1", "us-east-1", "cn-north-1"]
+  blocked_regions = ["cn-north-1"]
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["null.test"]
+}
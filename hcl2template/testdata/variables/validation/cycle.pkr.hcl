@@ -0,0 +1,25 @@
+
+variable "max_size" {
+  type    = number
+  default = 2
+  validation {
+    condition     = var.max_size < local.limit
+    error_message = "The max_size value must be lower than the limit."
+  }
+}
+
+locals {
+  limit = local.max_limit
+}
+
+locals {
+  max_limit = local.limit * 10
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["null.test"]
+}
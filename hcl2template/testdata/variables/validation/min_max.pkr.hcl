@@ -0,0 +1,26 @@
+
+variable "min_size" {
+  type    = number
+  default = 1
+  validation {
+    condition     = var.min_size < var.max_size
+    error_message = "The min_size value must be lower than max_size."
+  }
+}
+
+variable "max_size" {
+  type    = number
+  default = 2
+  validation {
+    condition     = var.max_size > var.min_size
+    error_message = "The max_size value must be greater than min_size."
+  }
+}
+
+source "null" "test" {
+  communicator = "none"
+}
+
+build {
+  sources = ["null.test"]
+}
@@ -0,0 +1,223 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	buildPreconditionLabel = "precondition"
+
+	buildPostconditionLabel = "postcondition"
+
+	artifactAccessor = "artifact"
+)
+
+var conditionBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition", Required: true},
+		{Name: "error_message", Required: true},
+	},
+}
+
+// ConditionBlock references an HCL 'precondition' or 'postcondition' block of
+// a build, or of a source used in a build:
+//
+//	build {
+//	  postcondition {
+//	    condition     = length(artifact.files) > 0
+//	    error_message = "The build must produce files."
+//	  }
+//	}
+type ConditionBlock struct {
+	// Condition must return true for the build to go on.
+	Condition hcl.Expression
+	// ErrorMessage is the error failing the build when Condition returns
+	// false. It is evaluated with the same values as Condition.
+	ErrorMessage hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+func (p *Parser) decodeConditionBlock(block *hcl.Block) (*ConditionBlock, hcl.Diagnostics) {
+	content, diags := block.Body.Content(conditionBlockSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	c := &ConditionBlock{
+		Condition:    content.Attributes["condition"].Expr,
+		ErrorMessage: content.Attributes["error_message"].Expr,
+		DeclRange:    block.DefRange,
+	}
+
+	if block.Type == buildPreconditionLabel {
+		// The artifact only exists once the builder ran.
+		for _, expr := range []hcl.Expression{c.Condition, c.ErrorMessage} {
+			for _, traversal := range expr.Variables() {
+				if traversal.RootName() != artifactAccessor {
+					continue
+				}
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid reference in " + buildPreconditionLabel,
+					Detail:   "The artifact of a build can only be referenced in a " + buildPostconditionLabel + " block.",
+					Subject:  traversal.SourceRange().Ptr(),
+				})
+			}
+		}
+	}
+
+	return c, diags
+}
+
+// check evaluates the condition, and returns an error diagnostic with the
+// error message when it doesn't hold.
+func (c *ConditionBlock) check(kind string, ectx *hcl.EvalContext) hcl.Diagnostics {
+	const errInvalidCondition = "Invalid condition result"
+
+	result, diags := c.Condition.Value(ectx)
+	if diags.HasErrors() {
+		return diags
+	}
+	if !result.IsKnown() {
+		log.Printf("[TRACE] %s %s value is unknown, so skipping", kind, c.DeclRange)
+		return diags
+	}
+	if result.IsNull() {
+		return append(diags, &hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     errInvalidCondition,
+			Detail:      "Condition expression must return either true or false, not null.",
+			Subject:     c.Condition.Range().Ptr(),
+			Expression:  c.Condition,
+			EvalContext: ectx,
+		})
+	}
+	result, err := convert.Convert(result, cty.Bool)
+	if err != nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     errInvalidCondition,
+			Detail:      fmt.Sprintf("Invalid condition result value: %s.", err),
+			Subject:     c.Condition.Range().Ptr(),
+			Expression:  c.Condition,
+			EvalContext: ectx,
+		})
+	}
+	if result.True() {
+		return diags
+	}
+
+	var message string
+	moreDiags := gohcl.DecodeExpression(c.ErrorMessage, ectx, &message)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+	return append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  kind + " failed",
+		Detail:   fmt.Sprintf("%s\n\nThis was checked by the %s at %s.", message, kind, c.DeclRange.String()),
+		Subject:  c.Condition.Range().Ptr(),
+	})
+}
+
+// buildConditions checks the preconditions and postconditions of a source
+// used in a build.
+type buildConditions struct {
+	preconditions  []*ConditionBlock
+	postconditions []*ConditionBlock
+	evalContext    *hcl.EvalContext
+}
+
+func (c *buildConditions) CheckPreconditions() error {
+	if diags := c.checkPreconditions(); diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
+
+// checkPreconditions evaluates the preconditions; the ones whose result is not
+// known yet, for example because they refer to a data source that was not
+// executed, are skipped.
+func (c *buildConditions) checkPreconditions() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, precondition := range c.preconditions {
+		diags = append(diags, precondition.check("Precondition", c.evalContext)...)
+	}
+	return diags
+}
+
+func (c *buildConditions) CheckPostconditions(artifact packersdk.Artifact) error {
+	ectx, err := c.artifactEvalContext(artifact)
+	if err != nil {
+		return err
+	}
+
+	var diags hcl.Diagnostics
+	for _, postcondition := range c.postconditions {
+		diags = append(diags, postcondition.check("Postcondition", ectx)...)
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
+
+// artifactEvalContext returns the context of postconditions: the artifact of
+// the builder is available as `artifact`, and its generated data in `build`,
+// like for post-processors.
+func (c *buildConditions) artifactEvalContext(artifact packersdk.Artifact) (*hcl.EvalContext, error) {
+	buildValues := map[string]cty.Value{}
+	if build, ok := c.evalContext.Variables[buildAccessor]; ok && !build.IsNull() {
+		buildValues = build.AsValueMap()
+	}
+	switch generatedData := artifact.State("generated_data").(type) {
+	case map[interface{}]interface{}:
+		for k, v := range generatedData {
+			val, err := ConvertPluginConfigValueToHCLValue(v)
+			if err != nil {
+				return nil, err
+			}
+			buildValues[fmt.Sprint(k)] = val
+		}
+	case map[string]interface{}:
+		for k, v := range generatedData {
+			val, err := ConvertPluginConfigValueToHCLValue(v)
+			if err != nil {
+				return nil, err
+			}
+			buildValues[k] = val
+		}
+	}
+
+	files := make([]cty.Value, 0, len(artifact.Files()))
+	for _, file := range artifact.Files() {
+		files = append(files, cty.StringVal(file))
+	}
+	filesVal := cty.ListValEmpty(cty.String)
+	if len(files) > 0 {
+		filesVal = cty.ListVal(files)
+	}
+
+	ectx := c.evalContext.NewChild()
+	ectx.Variables = map[string]cty.Value{
+		buildAccessor: cty.ObjectVal(buildValues),
+		artifactAccessor: cty.ObjectVal(map[string]cty.Value{
+			"id":         cty.StringVal(artifact.Id()),
+			"builder_id": cty.StringVal(artifact.BuilderId()),
+			"files":      filesVal,
+		}),
+	}
+	return ectx, nil
+}
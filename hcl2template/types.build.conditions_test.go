@@ -0,0 +1,182 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/builder/null"
+	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParse_conditions(t *testing.T) {
+	defaultParser := getBasicParser()
+
+	conditionsConfig := func(diskSize ...VariableAssignment) *PackerConfig {
+		return &PackerConfig{
+			CorePackerVersionString: lockedVersion,
+			Basedir:                 filepath.Join("testdata", "conditions"),
+			InputVariables: Variables{
+				"disk_size": &Variable{
+					Name: "disk_size",
+					Type: cty.Number,
+					Values: append([]VariableAssignment{
+						{From: "default", Value: cty.NumberIntVal(20)},
+					}, diskSize...),
+				},
+			},
+			Sources: map[SourceRef]SourceBlock{
+				refNull: {Type: "null", Name: "test"},
+			},
+			Builds: Builds{
+				&BuildBlock{
+					Name:           "conditions",
+					Preconditions:  []*ConditionBlock{{}},
+					Postconditions: []*ConditionBlock{{}},
+					Sources: []SourceUseBlock{
+						{
+							SourceRef:      refNull,
+							LocalName:      "local",
+							Postconditions: []*ConditionBlock{{}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []parseTest{
+		{"preconditions holding",
+			defaultParser,
+			parseTestArgs{"testdata/conditions/basic.pkr.hcl", nil, nil},
+			conditionsConfig(),
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					BuildName:      "conditions",
+					Type:           "null.local",
+					BuilderType:    "null",
+					Builder:        &null.Builder{},
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Conditions:     &buildConditions{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"failing precondition",
+			defaultParser,
+			parseTestArgs{"testdata/conditions/basic.pkr.hcl", map[string]string{"disk_size": "5"}, nil},
+			conditionsConfig(VariableAssignment{From: "cmd", Value: cty.NumberIntVal(5)}),
+			false, false,
+			[]*packer.CoreBuild{},
+			true,
+			nil,
+		},
+		{"precondition referring to the artifact",
+			defaultParser,
+			parseTestArgs{"testdata/conditions/invalid_precondition.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "conditions"),
+				Sources: map[SourceRef]SourceBlock{
+					refNull: {Type: "null", Name: "test"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+	}
+	testParse(t, tests)
+}
+
+func TestBuildConditions_CheckPostconditions(t *testing.T) {
+	expr := func(src string) hcl.Expression {
+		e, diags := hclsyntax.ParseExpression([]byte(src), "conditions.pkr.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("invalid expression %q: %s", src, diags)
+		}
+		return e
+	}
+	conditions := &buildConditions{
+		postconditions: []*ConditionBlock{
+			{
+				Condition:    expr(`length(artifact.files) > 0`),
+				ErrorMessage: expr(`"The build must produce files."`),
+			},
+			{
+				Condition:    expr(`startswith(artifact.id, "ami-") && build.ID == artifact.id`),
+				ErrorMessage: expr(`"The artifact ${artifact.id} of ${source.name} must be an AMI."`),
+			},
+		},
+		evalContext: &hcl.EvalContext{
+			Functions: Functions("."),
+			Variables: map[string]cty.Value{
+				sourcesAccessor: cty.ObjectVal(map[string]cty.Value{
+					"type": cty.StringVal("null"),
+					"name": cty.StringVal("local"),
+				}),
+				buildAccessor: cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("conditions"),
+				}),
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		artifact *packersdk.MockArtifact
+		wantErr  string
+	}{
+		{"valid artifact",
+			&packersdk.MockArtifact{
+				IdValue:     "ami-1234",
+				FilesValue:  []string{"manifest.json"},
+				StateValues: map[string]interface{}{"generated_data": map[interface{}]interface{}{"ID": "ami-1234"}},
+			},
+			"",
+		},
+		{"no files",
+			&packersdk.MockArtifact{
+				IdValue:     "ami-1234",
+				FilesValue:  []string{},
+				StateValues: map[string]interface{}{"generated_data": map[string]interface{}{"ID": "ami-1234"}},
+			},
+			"The build must produce files.",
+		},
+		{"generated data and source values",
+			&packersdk.MockArtifact{
+				IdValue:     "img-1234",
+				FilesValue:  []string{"manifest.json"},
+				StateValues: map[string]interface{}{"generated_data": map[interface{}]interface{}{"ID": "img-1234"}},
+			},
+			"The artifact img-1234 of local must be an AMI.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := conditions.CheckPostconditions(tt.artifact)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckPostconditions: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
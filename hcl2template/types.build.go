@@ -37,6 +37,9 @@ var buildSchema = &hcl.BodySchema{
 		{Type: buildPostProcessorLabel, LabelNames: []string{"type"}},
 		{Type: buildPostProcessorsLabel, LabelNames: []string{}},
 		{Type: buildHCPPackerRegistryLabel},
+		{Type: buildPreconditionLabel},
+		{Type: buildPostconditionLabel},
+		{Type: provisionerGroupLabel, LabelNames: []string{"name"}},
 	},
 }
 
@@ -82,6 +85,11 @@ type BuildBlock struct {
 	// steps.
 	PostProcessorsLists [][]*PostProcessorBlock
 
+	// Preconditions are checked before the builder of each source starts,
+	// and Postconditions against the artifact of each builder.
+	Preconditions  []*ConditionBlock
+	Postconditions []*ConditionBlock
+
 	HCL2Ref HCL2Ref
 }
 
@@ -173,12 +181,12 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 			build.HCPPackerRegistry = hcpPackerRegistry
 		case sourceLabel:
 			hadSource = true
-			ref, moreDiags := p.decodeBuildSource(block)
+			refs, moreDiags := p.decodeBuildSource(block, cfg.EvalContext(LocalContext, nil))
 			diags = append(diags, moreDiags...)
 			if moreDiags.HasErrors() {
 				continue
 			}
-			build.Sources = append(build.Sources, ref)
+			build.Sources = append(build.Sources, refs...)
 		case buildProvisionerLabel:
 			p, moreDiags := p.decodeProvisioner(block, ectx)
 			diags = append(diags, moreDiags...)
@@ -186,6 +194,13 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 				continue
 			}
 			build.ProvisionerBlocks = append(build.ProvisionerBlocks, p)
+		case provisionerGroupLabel:
+			ps, moreDiags := p.decodeProvisionerGroupUse(block, cfg, ectx)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+			build.ProvisionerBlocks = append(build.ProvisionerBlocks, ps...)
 		case buildErrorCleanupProvisionerLabel:
 			if build.ErrorCleanupProvisionerBlock != nil {
 				diags = append(diags, &hcl.Diagnostic{
@@ -208,6 +223,17 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 				continue
 			}
 			build.PostProcessorsLists = append(build.PostProcessorsLists, []*PostProcessorBlock{pp})
+		case buildPreconditionLabel, buildPostconditionLabel:
+			c, moreDiags := p.decodeConditionBlock(block)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+			if block.Type == buildPreconditionLabel {
+				build.Preconditions = append(build.Preconditions, c)
+			} else {
+				build.Postconditions = append(build.Postconditions, c)
+			}
 		case buildPostProcessorsLabel:
 
 			content, moreDiags := block.Body.Content(postProcessorsSchema)
@@ -22,6 +22,9 @@ type HCPPackerRegistryBlock struct {
 	BuildLabels map[string]string
 	// Channels
 	Channels []string
+	// Location of a self-hosted registry, a directory or an HTTP(S) URL.
+	// When empty, builds are published to HCP Packer.
+	Location string
 
 	HCL2Ref
 }
@@ -40,6 +43,7 @@ func (p *Parser) decodeHCPRegistry(block *hcl.Block, cfg *PackerConfig) (*HCPPac
 		BucketLabels map[string]string `hcl:"bucket_labels,optional"`
 		BuildLabels  map[string]string `hcl:"build_labels,optional"`
 		Channels     []string          `hcl:"channels,optional"`
+		Location     string            `hcl:"location,optional"`
 		Config       hcl.Body          `hcl:",remain"`
 	}
 	ectx := cfg.EvalContext(BuildContext, nil)
@@ -73,6 +77,7 @@ func (p *Parser) decodeHCPRegistry(block *hcl.Block, cfg *PackerConfig) (*HCPPac
 	par.Slug = b.Slug
 	par.Description = b.Description
 	par.Channels = b.Channels
+	par.Location = b.Location
 
 	if len(b.Labels) > 0 && len(b.BucketLabels) > 0 {
 		diags = append(diags, &hcl.Diagnostic{
@@ -18,6 +18,9 @@ type PostProcessorBlock struct {
 	PName             string
 	OnlyExcept        OnlyExcept
 	KeepInputArtifact *bool
+	// When is evaluated for each source of the build; the post-processor is
+	// skipped when it returns false.
+	When hcl.Expression
 
 	HCL2Ref
 }
@@ -28,11 +31,12 @@ func (p *PostProcessorBlock) String() string {
 
 func (p *Parser) decodePostProcessor(block *hcl.Block, ectx *hcl.EvalContext) (*PostProcessorBlock, hcl.Diagnostics) {
 	var b struct {
-		Name              string   `hcl:"name,optional"`
-		Only              []string `hcl:"only,optional"`
-		Except            []string `hcl:"except,optional"`
-		KeepInputArtifact *bool    `hcl:"keep_input_artifact,optional"`
-		Rest              hcl.Body `hcl:",remain"`
+		Name              string         `hcl:"name,optional"`
+		Only              []string       `hcl:"only,optional"`
+		Except            []string       `hcl:"except,optional"`
+		KeepInputArtifact *bool          `hcl:"keep_input_artifact,optional"`
+		When              hcl.Expression `hcl:"when,optional"`
+		Rest              hcl.Body       `hcl:",remain"`
 	}
 
 	diags := gohcl.DecodeBody(block.Body, ectx, &b)
@@ -46,6 +50,7 @@ func (p *Parser) decodePostProcessor(block *hcl.Block, ectx *hcl.EvalContext) (*
 		OnlyExcept:        OnlyExcept{Only: b.Only, Except: b.Except},
 		HCL2Ref:           newHCL2Ref(block, b.Rest),
 		KeepInputArtifact: b.KeepInputArtifact,
+		When:              b.When,
 	}
 
 	diags = diags.Extend(postProcessor.OnlyExcept.Validate())
@@ -76,6 +81,9 @@ func (cfg *PackerConfig) startPostProcessor(source SourceUseBlock, pp *PostProce
 	builderVars["packer_debug"] = strconv.FormatBool(cfg.debug)
 	builderVars["packer_force"] = strconv.FormatBool(cfg.force)
 	builderVars["packer_on_error"] = cfg.onError
+	builderVars["packer_user_variables"] = cfg.inputVariableStrings()
+	builderVars["packer_sensitive_variables"] = cfg.sensitiveInputVariableKeys()
+	builderVars["packer_sensitive_values"] = cfg.sensitiveVariableValues()
 
 	hclPostProcessor := &HCL2PostProcessor{
 		PostProcessor:      postProcessor,
@@ -5,6 +5,7 @@ package hcl2template
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/hashicorp/hcl/v2/gohcl"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	hcl2shim "github.com/hashicorp/packer/hcl2template/shim"
+	"github.com/hashicorp/packer/packer"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 // OnlyExcept is a struct that is meant to be embedded that contains the
@@ -61,6 +64,37 @@ func (o *OnlyExcept) Validate() hcl.Diagnostics {
 	return diags
 }
 
+// evaluateWhen tells whether the block owning the `when` expression should
+// run for a source. A missing or null expression always runs, and so does an
+// unknown one, for example when datasources are not executed.
+func evaluateWhen(when hcl.Expression, ectx *hcl.EvalContext) (bool, hcl.Diagnostics) {
+	if when == nil {
+		return true, nil
+	}
+	val, diags := when.Value(ectx)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	if val.IsNull() || !val.IsKnown() {
+		return true, diags
+	}
+	val, err := convert.Convert(val, cty.Bool)
+	if err != nil {
+		return false, append(diags, &hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid when argument",
+			Detail:      fmt.Sprintf("The when argument must be either true or false: %s.", err),
+			Subject:     when.Range().Ptr(),
+			Expression:  when,
+			EvalContext: ectx,
+		})
+	}
+	if !val.IsKnown() {
+		return true, diags
+	}
+	return val.True(), diags
+}
+
 // ProvisionerBlock references a detected but unparsed provisioner
 type ProvisionerBlock struct {
 	PType       string
@@ -68,25 +102,110 @@ type ProvisionerBlock struct {
 	PauseBefore time.Duration
 	MaxRetries  int
 	Timeout     time.Duration
-	Override    map[string]interface{}
-	OnlyExcept  OnlyExcept
+	// RetryBackoff and RetryOn tune how failed runs are retried when
+	// MaxRetries is set.
+	RetryBackoff packer.RetryBackoff
+	RetryOn      []*regexp.Regexp
+	Override     map[string]interface{}
+	OnlyExcept   OnlyExcept
+	// When is evaluated for each source of the build; the provisioner is
+	// skipped when it returns false.
+	When hcl.Expression
 	HCL2Ref
+
+	// group is set when the provisioner comes from a provisioner group.
+	group *provisionerGroupUse
+}
+
+// evalContext returns the context to evaluate the provisioner with, which
+// has the values of its provisioner group, if any.
+func (p *ProvisionerBlock) evalContext(ectx *hcl.EvalContext) (*hcl.EvalContext, hcl.Diagnostics) {
+	if p.group == nil {
+		return ectx, nil
+	}
+	return p.group.evalContext(ectx)
 }
 
 func (p *ProvisionerBlock) String() string {
 	return fmt.Sprintf(buildProvisionerLabel+"-block %q %q", p.PType, p.PName)
 }
 
+// retryBackoffBlock is the HCL representation of a packer.RetryBackoff.
+type retryBackoffBlock struct {
+	InitialDelay string   `hcl:"initial_delay,optional"`
+	Multiplier   *float64 `hcl:"multiplier,optional"`
+	MaxDelay     string   `hcl:"max_delay,optional"`
+	Jitter       float64  `hcl:"jitter,optional"`
+}
+
+func (rb *retryBackoffBlock) decode(subject *hcl.Range) (packer.RetryBackoff, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	backoff := packer.RetryBackoff{
+		Multiplier: 1,
+		Jitter:     rb.Jitter,
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"initial_delay", rb.InitialDelay, &backoff.InitialDelay},
+		{"max_delay", rb.MaxDelay, &backoff.MaxDelay},
+	} {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Summary:  fmt.Sprintf("Failed to parse retry_backoff %s duration", d.name),
+				Severity: hcl.DiagError,
+				Detail:   err.Error(),
+				Subject:  subject,
+			})
+			continue
+		}
+		*d.dst = duration
+	}
+
+	if rb.Multiplier != nil {
+		backoff.Multiplier = *rb.Multiplier
+	}
+	if rb.Multiplier != nil && *rb.Multiplier < 1 {
+		diags = append(diags, &hcl.Diagnostic{
+			Summary:  "Invalid retry_backoff multiplier",
+			Severity: hcl.DiagError,
+			Detail:   "multiplier must be greater than or equal to 1.",
+			Subject:  subject,
+		})
+	}
+
+	if rb.Jitter < 0 || rb.Jitter > 1 {
+		diags = append(diags, &hcl.Diagnostic{
+			Summary:  "Invalid retry_backoff jitter",
+			Severity: hcl.DiagError,
+			Detail:   "jitter must be a fraction between 0 and 1.",
+			Subject:  subject,
+		})
+	}
+
+	return backoff, diags
+}
+
 func (p *Parser) decodeProvisioner(block *hcl.Block, ectx *hcl.EvalContext) (*ProvisionerBlock, hcl.Diagnostics) {
 	var b struct {
-		Name        string    `hcl:"name,optional"`
-		PauseBefore string    `hcl:"pause_before,optional"`
-		MaxRetries  int       `hcl:"max_retries,optional"`
-		Timeout     string    `hcl:"timeout,optional"`
-		Only        []string  `hcl:"only,optional"`
-		Except      []string  `hcl:"except,optional"`
-		Override    cty.Value `hcl:"override,optional"`
-		Rest        hcl.Body  `hcl:",remain"`
+		Name         string             `hcl:"name,optional"`
+		PauseBefore  string             `hcl:"pause_before,optional"`
+		MaxRetries   int                `hcl:"max_retries,optional"`
+		RetryBackoff *retryBackoffBlock `hcl:"retry_backoff,block"`
+		RetryOn      []string           `hcl:"retry_on,optional"`
+		Timeout      string             `hcl:"timeout,optional"`
+		Only         []string           `hcl:"only,optional"`
+		Except       []string           `hcl:"except,optional"`
+		Override     cty.Value          `hcl:"override,optional"`
+		When         hcl.Expression     `hcl:"when,optional"`
+		Rest         hcl.Body           `hcl:",remain"`
 	}
 	diags := gohcl.DecodeBody(block.Body, ectx, &b)
 	if diags.HasErrors() {
@@ -98,6 +217,7 @@ func (p *Parser) decodeProvisioner(block *hcl.Block, ectx *hcl.EvalContext) (*Pr
 		PName:      b.Name,
 		MaxRetries: b.MaxRetries,
 		OnlyExcept: OnlyExcept{Only: b.Only, Except: b.Except},
+		When:       b.When,
 		HCL2Ref:    newHCL2Ref(block, b.Rest),
 	}
 
@@ -163,6 +283,28 @@ func (p *Parser) decodeProvisioner(block *hcl.Block, ectx *hcl.EvalContext) (*Pr
 		provisioner.Timeout = timeout
 	}
 
+	if b.RetryBackoff != nil {
+		backoff, moreDiags := b.RetryBackoff.decode(&block.DefRange)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		provisioner.RetryBackoff = backoff
+	}
+
+	for _, expr := range b.RetryOn {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, append(diags, &hcl.Diagnostic{
+				Summary:  fmt.Sprintf("Failed to compile retry_on expression %q", expr),
+				Severity: hcl.DiagError,
+				Detail:   err.Error(),
+				Subject:  &block.DefRange,
+			})
+		}
+		provisioner.RetryOn = append(provisioner.RetryOn, re)
+	}
+
 	return provisioner, diags
 }
 
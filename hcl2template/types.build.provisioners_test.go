@@ -34,6 +34,30 @@ func TestPackerConfig_ParseProvisionerBlock(t *testing.T) {
 			true,
 			"provisioner's override.'test' block must be an HCL object",
 		},
+		{
+			"success - provisioner retry_backoff is valid",
+			"fixtures/well_formed_retry_backoff.pkr.hcl",
+			false,
+			"",
+		},
+		{
+			"failure - provisioner retry_backoff jitter is out of range",
+			"fixtures/malformed_retry_backoff_jitter.pkr.hcl",
+			true,
+			"Invalid retry_backoff jitter",
+		},
+		{
+			"failure - provisioner retry_backoff multiplier is lower than 1",
+			"fixtures/malformed_retry_backoff_multiplier.pkr.hcl",
+			true,
+			"Invalid retry_backoff multiplier",
+		},
+		{
+			"failure - provisioner retry_on is not a valid expression",
+			"fixtures/malformed_retry_on.pkr.hcl",
+			true,
+			`Failed to compile retry_on expression "mirror (unavailable"`,
+		},
 	}
 
 	for _, test := range tests {
@@ -722,3 +722,176 @@ func TestParse_build(t *testing.T) {
 	}
 	testParse(t, tests)
 }
+
+func TestParse_buildWhen(t *testing.T) {
+	defaultParser := getBasicParser()
+
+	whenConfig := func(environment ...VariableAssignment) *PackerConfig {
+		return &PackerConfig{
+			CorePackerVersionString: lockedVersion,
+			Basedir:                 filepath.Join("testdata", "build"),
+			InputVariables: Variables{
+				"environment": &Variable{
+					Name: "environment",
+					Type: cty.String,
+					Values: append([]VariableAssignment{
+						{From: "default", Value: cty.StringVal("dev")},
+					}, environment...),
+				},
+			},
+			LocalVariables: Variables{
+				"production": &Variable{
+					Name: "production",
+					Type: cty.Bool,
+					Values: []VariableAssignment{{
+						From:  "default",
+						Value: cty.BoolVal(len(environment) > 0),
+					}},
+				},
+			},
+			Sources: map[SourceRef]SourceBlock{
+				refVBIsoUbuntu1204:  {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				refAWSEBSUbuntu1604: {Type: "amazon-ebs", Name: "ubuntu-1604"},
+			},
+			Builds: Builds{
+				&BuildBlock{
+					Sources: []SourceUseBlock{
+						{SourceRef: refVBIsoUbuntu1204},
+						{SourceRef: refAWSEBSUbuntu1604},
+					},
+					ProvisionerBlocks: []*ProvisionerBlock{
+						{PType: "shell", PName: "hardening"},
+						{PType: "file", PName: "aws-only"},
+					},
+					ErrorCleanupProvisionerBlock: &ProvisionerBlock{PType: "shell"},
+					PostProcessorsLists: [][]*PostProcessorBlock{
+						{{PType: "manifest"}},
+					},
+				},
+			},
+		}
+	}
+	whenProvisioner := func(pType, pName string) packer.CoreBuildProvisioner {
+		return packer.CoreBuildProvisioner{
+			PType: pType,
+			PName: pName,
+			Provisioner: &HCL2Provisioner{
+				Provisioner: &MockProvisioner{
+					Config: MockConfig{
+						NestedMockConfig: NestedMockConfig{Tags: []MockTag{}},
+						NestedSlice:      []NestedMockConfig{},
+					},
+				},
+			},
+		}
+	}
+	whenPostProcessors := [][]packer.CoreBuildPostProcessor{
+		{
+			{
+				PType: "manifest",
+				PostProcessor: &HCL2PostProcessor{
+					PostProcessor: &MockPostProcessor{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{Tags: []MockTag{}},
+							NestedSlice:      []NestedMockConfig{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []parseTest{
+		{"steps skipped by when",
+			defaultParser,
+			parseTestArgs{"testdata/build/when.pkr.hcl", nil, nil},
+			whenConfig(),
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					Type:           "virtualbox-iso.ubuntu-1204",
+					BuilderType:    "virtualbox-iso",
+					Builder:        emptyMockBuilder,
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					Type:        "amazon-ebs.ubuntu-1604",
+					BuilderType: "amazon-ebs",
+					Builder:     emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						whenProvisioner("file", "aws-only"),
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"steps run by when",
+			defaultParser,
+			parseTestArgs{"testdata/build/when.pkr.hcl", map[string]string{"environment": "production"}, nil},
+			whenConfig(VariableAssignment{From: "cmd", Value: cty.StringVal("production")}),
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					Type:        "virtualbox-iso.ubuntu-1204",
+					BuilderType: "virtualbox-iso",
+					Builder:     emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						whenProvisioner("shell", "hardening"),
+					},
+					CleanupProvisioner: whenProvisioner("shell", ""),
+					PostProcessors:     whenPostProcessors,
+					Prepared:           true,
+					SensitiveVars:      []string{},
+				},
+				&packer.CoreBuild{
+					Type:        "amazon-ebs.ubuntu-1604",
+					BuilderType: "amazon-ebs",
+					Builder:     emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						whenProvisioner("shell", "hardening"),
+						whenProvisioner("file", "aws-only"),
+					},
+					CleanupProvisioner: whenProvisioner("shell", ""),
+					PostProcessors:     whenPostProcessors,
+					Prepared:           true,
+					SensitiveVars:      []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"when is not a bool",
+			defaultParser,
+			parseTestArgs{"testdata/build/when_invalid.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{SourceRef: refVBIsoUbuntu1204},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{PType: "shell"},
+						},
+					},
+				},
+			},
+			false, false,
+			[]*packer.CoreBuild{},
+			true,
+			nil,
+		},
+	}
+	testParse(t, tests)
+}
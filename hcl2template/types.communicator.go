@@ -0,0 +1,190 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const communicatorAccessor = "communicator"
+
+// communicatorTypes are the types of communicator blocks, as set in the
+// `communicator` argument of sources.
+var communicatorTypes = []string{"ssh", "winrm"}
+
+// CommunicatorRef references a top-level communicator block.
+type CommunicatorRef struct {
+	Type string
+	Name string
+}
+
+func (r CommunicatorRef) String() string {
+	return fmt.Sprintf("%s.%s.%s", communicatorAccessor, r.Type, r.Name)
+}
+
+// CommunicatorBlock is a reusable set of communicator settings, that sources
+// reference with `communicator = communicator.<type>.<name>`:
+//
+//	communicator "ssh" "bastion" {
+//	  ssh_bastion_host = "bastion.example.com"
+//	  ssh_timeout      = "10m"
+//	}
+type CommunicatorBlock struct {
+	// Type of communicator; ex: ssh
+	Type string
+	// Given name
+	Name string
+
+	block *hcl.Block
+}
+
+func (c *CommunicatorBlock) Ref() CommunicatorRef {
+	return CommunicatorRef{Type: c.Type, Name: c.Name}
+}
+
+func (p *Parser) decodeCommunicator(block *hcl.Block) (*CommunicatorBlock, hcl.Diagnostics) {
+	c := &CommunicatorBlock{
+		Type:  block.Labels[0],
+		Name:  block.Labels[1],
+		block: block,
+	}
+
+	for _, t := range communicatorTypes {
+		if c.Type == t {
+			return c, nil
+		}
+	}
+	return nil, hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unknown " + communicatorLabel + " type " + c.Type,
+		Detail:   fmt.Sprintf("Known types are %v.", communicatorTypes),
+		Subject:  block.LabelRanges[0].Ptr(),
+	}}
+}
+
+// withCommunicator returns the body of a source, with the settings of the
+// communicator block it references in its `communicator` argument, if any.
+func (cfg *PackerConfig) withCommunicator(body hcl.Body) (hcl.Body, hcl.Diagnostics) {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: communicatorAccessor}},
+	})
+	attr, ok := content.Attributes[communicatorAccessor]
+	if !ok {
+		return body, nil
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+	if diags.HasErrors() || traversal.RootName() != communicatorAccessor {
+		// This is the name of a communicator, like "ssh".
+		return body, nil
+	}
+
+	ref, ok := communicatorRefFromTraversal(traversal)
+	if !ok {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid " + communicatorLabel + " reference",
+			Detail:   "A communicator block is referenced as communicator.<type>.<name>.",
+			Subject:  attr.Expr.Range().Ptr(),
+		}}
+	}
+	communicator, ok := cfg.Communicators[ref]
+	if !ok {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unknown " + communicatorLabel + " " + ref.String(),
+			Detail:   fmt.Sprintf("Known: %v", listAvailableCommunicatorNames(cfg.Communicators)),
+			Subject:  attr.Expr.Range().Ptr(),
+		}}
+	}
+
+	return &communicatorBody{Body: body, attr: attr, communicator: communicator}, nil
+}
+
+func communicatorRefFromTraversal(t hcl.Traversal) (CommunicatorRef, bool) {
+	if len(t) != 3 {
+		return CommunicatorRef{}, false
+	}
+	typ, ok := t[1].(hcl.TraverseAttr)
+	if !ok {
+		return CommunicatorRef{}, false
+	}
+	name, ok := t[2].(hcl.TraverseAttr)
+	if !ok {
+		return CommunicatorRef{}, false
+	}
+	return CommunicatorRef{Type: typ.Name, Name: name.Name}, true
+}
+
+func listAvailableCommunicatorNames(communicators map[CommunicatorRef]*CommunicatorBlock) []string {
+	res := make([]string, 0, len(communicators))
+	for k := range communicators {
+		res = append(res, k.String())
+	}
+	sort.Strings(res)
+	return res
+}
+
+// communicatorBody is the body of a source merged with the communicator block
+// it references: the arguments of the source take precedence over the ones of
+// the communicator block, and its `communicator` argument is set to the type
+// of the communicator block.
+type communicatorBody struct {
+	hcl.Body
+
+	// attr is the `communicator` argument referencing the communicator.
+	attr         *hcl.Attribute
+	communicator *CommunicatorBlock
+}
+
+func (b *communicatorBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := b.Body.Content(schema)
+	communicatorContent, moreDiags := b.communicator.block.Body.Content(schema)
+	diags = append(diags, moreDiags...)
+	return b.merge(content, communicatorContent), diags
+}
+
+func (b *communicatorBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content, remain, diags := b.Body.PartialContent(schema)
+	communicatorContent, _, moreDiags := b.communicator.block.Body.PartialContent(schema)
+	diags = append(diags, moreDiags...)
+	return b.merge(content, communicatorContent), remain, diags
+}
+
+func (b *communicatorBody) merge(content, communicatorContent *hcl.BodyContent) *hcl.BodyContent {
+	attrs := make(hcl.Attributes, len(content.Attributes)+len(communicatorContent.Attributes))
+	for name, attr := range communicatorContent.Attributes {
+		if name == communicatorAccessor {
+			continue
+		}
+		attrs[name] = attr
+	}
+	for name, attr := range content.Attributes {
+		attrs[name] = attr
+	}
+	if _, ok := attrs[communicatorAccessor]; ok {
+		attrs[communicatorAccessor] = &hcl.Attribute{
+			Name:      b.attr.Name,
+			Expr:      hcl.StaticExpr(cty.StringVal(b.communicator.Type), b.attr.Expr.Range()),
+			Range:     b.attr.Range,
+			NameRange: b.attr.NameRange,
+		}
+	}
+
+	blocks := content.Blocks
+	for _, block := range communicatorContent.Blocks {
+		if len(content.Blocks.OfType(block.Type)) == 0 {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return &hcl.BodyContent{
+		Attributes:       attrs,
+		Blocks:           blocks,
+		MissingItemRange: content.MissingItemRange,
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/hashicorp/packer/hcl2template/internal"
+	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestParse_communicator(t *testing.T) {
+	defaultParser := getBasicParser()
+
+	tests := []parseTest{
+		{"sources referencing a communicator block",
+			defaultParser,
+			parseTestArgs{"testdata/communicator/reference.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "communicator"),
+				InputVariables: Variables{
+					"username": &Variable{
+						Name:   "username",
+						Type:   cty.String,
+						Values: []VariableAssignment{{From: "default", Value: cty.StringVal("packer")}},
+					},
+				},
+				Communicators: map[CommunicatorRef]*CommunicatorBlock{
+					{Type: "ssh", Name: "bastion"}: {Type: "ssh", Name: "bastion"},
+				},
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoWeb: {Type: "virtualbox-iso", Name: "web"},
+					refVBIsoDB:  {Type: "virtualbox-iso", Name: "db"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{SourceRef: refVBIsoWeb},
+							{SourceRef: refVBIsoDB},
+						},
+					},
+				},
+			},
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					Type:        "virtualbox-iso.web",
+					BuilderType: "virtualbox-iso",
+					Prepared:    true,
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							Communicator: "ssh",
+							NestedMockConfig: NestedMockConfig{
+								String:   "packer",
+								Int:      42,
+								Duration: 10 * time.Minute,
+								Tags:     []MockTag{},
+							},
+							Nested: NestedMockConfig{
+								String: "bastion",
+								Tags:   []MockTag{},
+							},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					Type:        "virtualbox-iso.db",
+					BuilderType: "virtualbox-iso",
+					Prepared:    true,
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							Communicator: "ssh",
+							NestedMockConfig: NestedMockConfig{
+								String:   "packer",
+								Int:      42,
+								Duration: 5 * time.Minute,
+								Tags:     []MockTag{},
+							},
+							Nested: NestedMockConfig{
+								Int:  1,
+								Tags: []MockTag{},
+							},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					SensitiveVars:  []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"duplicate communicator block",
+			defaultParser,
+			parseTestArgs{"testdata/communicator/duplicate.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "communicator"),
+				Communicators: map[CommunicatorRef]*CommunicatorBlock{
+					{Type: "ssh", Name: "bastion"}: {Type: "ssh", Name: "bastion"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+		{"unknown communicator type",
+			defaultParser,
+			parseTestArgs{"testdata/communicator/invalid_type.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "communicator"),
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+		{"unknown communicator block",
+			defaultParser,
+			parseTestArgs{"testdata/communicator/unknown.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "communicator"),
+				Communicators: map[CommunicatorRef]*CommunicatorBlock{
+					{Type: "ssh", Name: "bastion"}: {Type: "ssh", Name: "bastion"},
+				},
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoWeb: {Type: "virtualbox-iso", Name: "web"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{SourceRef: refVBIsoWeb},
+						},
+					},
+				},
+			},
+			false, false,
+			[]*packer.CoreBuild{},
+			true,
+			nil,
+		},
+	}
+	testParse(t, tests)
+}
+
+var (
+	refVBIsoWeb = SourceRef{Type: "virtualbox-iso", Name: "web"}
+	refVBIsoDB  = SourceRef{Type: "virtualbox-iso", Name: "db"}
+)
@@ -4,11 +4,18 @@
 package hcl2template
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer/builder/null"
+	dnull "github.com/hashicorp/packer/datasource/null"
 	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestParse_datasource(t *testing.T) {
@@ -261,3 +268,66 @@ func TestParse_datasource(t *testing.T) {
 
 	testParse(t, tests)
 }
+
+// barrierDatasource is a null datasource whose execution only completes once
+// every datasource sharing the barrier is executing.
+type barrierDatasource struct {
+	dnull.Datasource
+	barrier *sync.WaitGroup
+}
+
+func (d *barrierDatasource) Execute() (cty.Value, error) {
+	d.barrier.Done()
+	done := make(chan struct{})
+	go func() {
+		d.barrier.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		return cty.NilVal, errors.New("datasources were not executed concurrently")
+	}
+	return d.Datasource.Execute()
+}
+
+func TestParse_datasourceConcurrentEvaluation(t *testing.T) {
+	const template = `
+data "barrier" "a" {
+  input = "a"
+}
+data "barrier" "b" {
+  input = "b"
+}
+data "barrier" "c" {
+  input = "c"
+}
+locals {
+  all = join(",", [data.barrier.a.output, data.barrier.b.output, data.barrier.c.output])
+}
+`
+	filename := filepath.Join(t.TempDir(), "concurrent.pkr.hcl")
+	if err := os.WriteFile(filename, []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	barrier := &sync.WaitGroup{}
+	barrier.Add(3)
+	parser := getBasicParser(func(p *Parser) {
+		p.PluginConfig.DataSources.Set("barrier", func() (packersdk.Datasource, error) {
+			return &barrierDatasource{barrier: barrier}, nil
+		})
+	})
+
+	cfg, diags := parser.Parse(filename, nil, nil)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse diags: %s", diags)
+	}
+	if diags := cfg.Initialize(packer.InitializeOptions{}); diags.HasErrors() {
+		t.Fatalf("unexpected initialize diags: %s", diags)
+	}
+
+	if got := cfg.LocalVariables["all"].Value(); !got.RawEquals(cty.StringVal("a,b,c")) {
+		t.Fatalf("unexpected local value: %#v", got)
+	}
+}
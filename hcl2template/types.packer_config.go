@@ -4,18 +4,25 @@
 package hcl2template
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gobwas/glob"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/fix"
 	pkrfunction "github.com/hashicorp/packer/hcl2template/function"
 	"github.com/hashicorp/packer/packer"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 // PackerConfig represents a loaded Packer HCL config. It will contain
@@ -38,6 +45,12 @@ type PackerConfig struct {
 	// Available Source blocks
 	Sources map[SourceRef]SourceBlock
 
+	// Available top-level communicator blocks, that sources can reference
+	Communicators map[CommunicatorRef]*CommunicatorBlock
+
+	// Available top-level provisioner groups, that builds can include
+	ProvisionerGroups map[string]*ProvisionerGroupBlock
+
 	// InputVariables and LocalVariables are the list of defined input and
 	// local variables. They are of the same type but are not used in the same
 	// way. Local variables will not be decoded from any config file, env var,
@@ -60,6 +73,10 @@ type PackerConfig struct {
 	// HCPVars is the list of HCP-set variables for use later in a template
 	HCPVars map[string]cty.Value
 
+	// envMetadata is the metadata gathered from the environment of the build,
+	// exposed as `packer.build_metadata`.
+	envMetadata map[string]interface{}
+
 	parser *Parser
 	files  []*hcl.File
 
@@ -83,6 +100,7 @@ const (
 	buildAccessor          = "build"
 	packerAccessor         = "packer"
 	dataAccessor           = "data"
+	eachAccessor           = "each"
 )
 
 type BlockContext int
@@ -122,6 +140,7 @@ func (cfg *PackerConfig) EvalContext(ctx BlockContext, variables map[string]cty.
 		"version":            cty.StringVal(cfg.CorePackerVersionString),
 		"iterationID":        cty.UnknownVal(cty.String),
 		"versionFingerprint": cty.UnknownVal(cty.String),
+		"build_metadata":     cty.DynamicVal,
 	}
 
 	iterID, ok := cfg.HCPVars["iterationID"]
@@ -133,6 +152,12 @@ func (cfg *PackerConfig) EvalContext(ctx BlockContext, variables map[string]cty.
 		packerVars["versionFingerprint"] = versionFP
 	}
 
+	if cfg.envMetadata != nil {
+		if buildMetadata, err := ConvertPluginConfigValueToHCLValue(cfg.envMetadata); err == nil {
+			packerVars["build_metadata"] = buildMetadata
+		}
+	}
+
 	ectx.Variables[packerAccessor] = cty.ObjectVal(packerVars)
 
 	// In the future we'd like to load and execute HCL blocks using a graph
@@ -489,42 +514,59 @@ func (cfg *PackerConfig) recursivelyEvaluateDatasources(ref DatasourceRef, depen
 	return dependencies, diags
 }
 
-func (cfg *PackerConfig) evaluateDatasource(ds DatasourceBlock, skipExecution bool) hcl.Diagnostics {
-	var diags hcl.Diagnostics
-
+// evaluateDatasource starts and executes a datasource, and records its value.
+// lock guards the state of cfg; it is not held while the datasource executes,
+// so that several datasources can execute concurrently.
+func (cfg *PackerConfig) evaluateDatasource(ds DatasourceBlock, skipExecution bool, lock sync.Locker) hcl.Diagnostics {
 	// If we've gotten here, then it means ref doesn't seem to have any further
 	// dependencies we need to evaluate first. Evaluate it, with the cfg's full
 	// data source context.
-	datasource, startDiags := cfg.startDatasource(ds)
-	if startDiags.HasErrors() {
-		diags = append(diags, startDiags...)
-		return diags
+	start := func() (packersdk.Datasource, *packer.TelemetrySpan, hcl.Diagnostics) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		datasource, diags := cfg.startDatasource(ds)
+		if diags.HasErrors() {
+			return nil, nil, diags
+		}
+
+		if skipExecution {
+			placeholderValue := cty.UnknownVal(hcldec.ImpliedType(datasource.OutputSpec()))
+			ds.value = placeholderValue
+			cfg.Datasources[ds.Ref()] = ds
+			return nil, nil, diags
+		}
+
+		opts, _ := decodeHCL2Spec(ds.block.Body, cfg.EvalContext(DatasourceContext, nil), datasource)
+		return datasource, packer.CheckpointReporter.AddSpan(ds.Ref().Type, "datasource", opts), diags
 	}
 
-	if skipExecution {
-		placeholderValue := cty.UnknownVal(hcldec.ImpliedType(datasource.OutputSpec()))
-		ds.value = placeholderValue
+	record := func(value cty.Value, err error) hcl.Diagnostics {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if err != nil {
+			return hcl.Diagnostics{&hcl.Diagnostic{
+				Summary:  err.Error(),
+				Subject:  &cfg.Datasources[ds.Ref()].block.DefRange,
+				Severity: hcl.DiagError,
+			}}
+		}
+
+		ds.value = value
 		cfg.Datasources[ds.Ref()] = ds
-		return diags
+		return nil
 	}
 
-	opts, _ := decodeHCL2Spec(ds.block.Body, cfg.EvalContext(DatasourceContext, nil), datasource)
-	sp := packer.CheckpointReporter.AddSpan(ds.Ref().Type, "datasource", opts)
-	realValue, err := datasource.Execute()
-	sp.End(err)
-	if err != nil {
-		diags = append(diags, &hcl.Diagnostic{
-			Summary:  err.Error(),
-			Subject:  &cfg.Datasources[ds.Ref()].block.DefRange,
-			Severity: hcl.DiagError,
-		})
+	datasource, sp, diags := start()
+	if datasource == nil {
 		return diags
 	}
 
-	ds.value = realValue
-	cfg.Datasources[ds.Ref()] = ds
+	realValue, err := datasource.Execute()
+	sp.End(err)
 
-	return diags
+	return append(diags, record(realValue, err)...)
 }
 
 // getCoreBuildProvisioners takes a list of provisioner block, starts according
@@ -536,6 +578,16 @@ func (cfg *PackerConfig) getCoreBuildProvisioners(source SourceUseBlock, blocks
 		if pb.OnlyExcept.Skip(source.String()) {
 			continue
 		}
+		whenCtx, moreDiags := pb.evalContext(cfg.whenEvalContext(source))
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		run, moreDiags := evaluateWhen(pb.When, whenCtx)
+		diags = append(diags, moreDiags...)
+		if !run {
+			continue
+		}
 
 		coreBuildProv, moreDiags := cfg.getCoreBuildProvisioner(source, pb, ectx)
 		diags = append(diags, moreDiags...)
@@ -548,7 +600,10 @@ func (cfg *PackerConfig) getCoreBuildProvisioners(source SourceUseBlock, blocks
 }
 
 func (cfg *PackerConfig) getCoreBuildProvisioner(source SourceUseBlock, pb *ProvisionerBlock, ectx *hcl.EvalContext) (packer.CoreBuildProvisioner, hcl.Diagnostics) {
-	var diags hcl.Diagnostics
+	ectx, diags := pb.evalContext(ectx)
+	if diags.HasErrors() {
+		return packer.CoreBuildProvisioner{}, diags
+	}
 	provisioner, moreDiags := cfg.startProvisioner(source, pb, ectx)
 	diags = append(diags, moreDiags...)
 	if moreDiags.HasErrors() {
@@ -572,6 +627,8 @@ func (cfg *PackerConfig) getCoreBuildProvisioner(source SourceUseBlock, pb *Prov
 	if pb.MaxRetries != 0 {
 		provisioner = &packer.RetriedProvisioner{
 			MaxRetries:  pb.MaxRetries,
+			Backoff:     pb.RetryBackoff,
+			RetryOn:     pb.RetryOn,
 			Provisioner: provisioner,
 		}
 	}
@@ -590,6 +647,14 @@ func (cfg *PackerConfig) getCoreBuildProvisioner(source SourceUseBlock, pb *Prov
 	}, diags
 }
 
+// whenEvalContext returns the context of the `when` argument of provisioner
+// and post-processor blocks for a source. Generated build values are not
+// known yet, so only variables, locals, datasources and the source can be
+// used.
+func (cfg *PackerConfig) whenEvalContext(source SourceUseBlock) *hcl.EvalContext {
+	return cfg.EvalContext(BuildContext, source.ctyVariables())
+}
+
 // getCoreBuildProvisioners takes a list of post processor block, starts
 // according provisioners and sends parsed HCL2 over to it.
 func (cfg *PackerConfig) getCoreBuildPostProcessors(source SourceUseBlock, blocksList [][]*PostProcessorBlock, ectx *hcl.EvalContext, exceptMatches *int) ([][]packer.CoreBuildPostProcessor, hcl.Diagnostics) {
@@ -601,6 +666,11 @@ func (cfg *PackerConfig) getCoreBuildPostProcessors(source SourceUseBlock, block
 			if ppb.OnlyExcept.Skip(source.String()) {
 				continue
 			}
+			run, moreDiags := evaluateWhen(ppb.When, cfg.whenEvalContext(source))
+			diags = append(diags, moreDiags...)
+			if !run {
+				continue
+			}
 
 			name := ppb.PName
 			if name == "" {
@@ -725,8 +795,9 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 			}
 
 			pcb := &packer.CoreBuild{
-				BuildName: build.Name,
-				Type:      srcUsage.String(),
+				BuildName:   build.Name,
+				Type:        srcUsage.String(),
+				EnvMetadata: cfg.envMetadata,
 			}
 
 			pcb.SetDebug(cfg.debug)
@@ -782,7 +853,7 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 				continue
 			}
 
-			decoded, _ := decodeHCL2Spec(srcUsage.Body, cfg.EvalContext(BuildContext, nil), builder)
+			decoded, _ := decodeHCL2Spec(srcUsage.Body, cfg.EvalContext(BuildContext, srcUsage.ctyVariables()), builder)
 			pcb.HCLConfig = decoded
 			pcb.BuilderType = srcUsage.Type
 
@@ -797,10 +868,8 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 			}
 			unknownBuildValues["name"] = cty.StringVal(build.Name)
 
-			variables := map[string]cty.Value{
-				sourcesAccessor: cty.ObjectVal(srcUsage.ctyValues()),
-				buildAccessor:   cty.ObjectVal(unknownBuildValues),
-			}
+			variables := srcUsage.ctyVariables()
+			variables[buildAccessor] = cty.ObjectVal(unknownBuildValues)
 
 			provisioners, moreDiags := cfg.getCoreBuildProvisioners(srcUsage, build.ProvisionerBlocks, cfg.EvalContext(BuildContext, variables))
 			diags = append(diags, moreDiags...)
@@ -813,8 +882,16 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 				continue
 			}
 
-			if build.ErrorCleanupProvisionerBlock != nil &&
-				!build.ErrorCleanupProvisionerBlock.OnlyExcept.Skip(srcUsage.String()) {
+			runErrorCleanup := build.ErrorCleanupProvisionerBlock != nil &&
+				!build.ErrorCleanupProvisionerBlock.OnlyExcept.Skip(srcUsage.String())
+			if runErrorCleanup {
+				runErrorCleanup, moreDiags = evaluateWhen(build.ErrorCleanupProvisionerBlock.When, cfg.whenEvalContext(srcUsage))
+				diags = append(diags, moreDiags...)
+				if moreDiags.HasErrors() {
+					continue
+				}
+			}
+			if runErrorCleanup {
 				errorCleanupProv, moreDiags := cfg.getCoreBuildProvisioner(srcUsage, build.ErrorCleanupProvisionerBlock, cfg.EvalContext(BuildContext, variables))
 				diags = append(diags, moreDiags...)
 				if moreDiags.HasErrors() {
@@ -823,6 +900,18 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 				pcb.CleanupProvisioner = errorCleanupProv
 			}
 
+			if conditions := cfg.getBuildConditions(build, srcUsage); conditions != nil {
+				// Check the preconditions now so that validate reports them;
+				// the ones referring to unknown values are checked again when
+				// the build runs.
+				moreDiags := conditions.checkPreconditions()
+				diags = append(diags, moreDiags...)
+				if moreDiags.HasErrors() {
+					continue
+				}
+				pcb.Conditions = conditions
+			}
+
 			pcb.Builder = builder
 			pcb.Provisioners = provisioners
 			pcb.PostProcessors = pps
@@ -865,6 +954,28 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]*packer.Core
 	return res, diags
 }
 
+// getBuildConditions returns the preconditions and postconditions of a source
+// used in a build, or nil when there are none. They are evaluated with the
+// values of the source and the name of the build; postconditions also get the
+// values of the artifact.
+func (cfg *PackerConfig) getBuildConditions(build *BuildBlock, srcUsage SourceUseBlock) *buildConditions {
+	conditions := &buildConditions{}
+	conditions.preconditions = append(conditions.preconditions, build.Preconditions...)
+	conditions.preconditions = append(conditions.preconditions, srcUsage.Preconditions...)
+	conditions.postconditions = append(conditions.postconditions, build.Postconditions...)
+	conditions.postconditions = append(conditions.postconditions, srcUsage.Postconditions...)
+	if len(conditions.preconditions) == 0 && len(conditions.postconditions) == 0 {
+		return nil
+	}
+
+	variables := srcUsage.ctyVariables()
+	variables[buildAccessor] = cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal(build.Name),
+	})
+	conditions.evalContext = cfg.EvalContext(BuildContext, variables)
+	return conditions
+}
+
 var PackerConsoleHelp = strings.TrimSpace(`
 Packer console HCL2 Mode.
 The Packer console allows you to experiment with Packer interpolations.
@@ -932,6 +1043,53 @@ func (cfg *PackerConfig) sensitiveInputVariableKeys() []string {
 	return sensitiveVars
 }
 
+// sensitiveVariableValues returns the strings of the sensitive input and local
+// variables, including the ones nested in complex values, so that
+// post-processors can redact them from what they write.
+func (cfg *PackerConfig) sensitiveVariableValues() []string {
+	var values []string
+	for _, variables := range []Variables{cfg.InputVariables, cfg.LocalVariables} {
+		for _, variable := range variables {
+			if !variable.Sensitive {
+				continue
+			}
+			_ = cty.Walk(variable.Value(), func(_ cty.Path, nested cty.Value) (bool, error) {
+				if nested.IsWhollyKnown() && !nested.IsNull() && nested.Type().Equals(cty.String) && nested.AsString() != "" {
+					values = append(values, nested.AsString())
+				}
+				return true, nil
+			})
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// inputVariableStrings returns the values of the non-sensitive input
+// variables as strings, complex values being JSON encoded.
+func (cfg *PackerConfig) inputVariableStrings() map[string]string {
+	vars := make(map[string]string, len(cfg.InputVariables))
+
+	for key, variable := range cfg.InputVariables {
+		if variable.Sensitive {
+			continue
+		}
+		value := variable.Value()
+		if !value.IsWhollyKnown() || value.IsNull() {
+			continue
+		}
+		if str, err := convert.Convert(value, cty.String); err == nil {
+			vars[key] = str.AsString()
+			continue
+		}
+		if b, err := ctyjson.Marshal(value, value.Type()); err == nil {
+			vars[key] = string(b)
+		}
+	}
+
+	return vars
+}
+
 func (p *PackerConfig) printBuilds() string {
 	out := &strings.Builder{}
 	out.WriteString("> builds:\n")
@@ -998,14 +1156,54 @@ func (p *PackerConfig) handleEval(line string) (out string, exit bool, diags hcl
 	return PrintableCtyValue(val), false, diags
 }
 
-func (p *PackerConfig) FixConfig(_ packer.FixConfigOptions) (diags hcl.Diagnostics) {
-	// No Fixers exist for HCL2 configs so there is nothing to do here for now.
-	return
+// FixConfig reports the HCL2 configuration files that `packer fix` would
+// change. Only the Diff mode is supported, the files are fixed by the fix
+// command directly.
+func (p *PackerConfig) FixConfig(opts packer.FixConfigOptions) (diags hcl.Diagnostics) {
+	if opts.Mode != packer.Diff {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("FixConfig only supports template diff; FixConfigMode %d not supported", opts.Mode),
+		})
+	}
+
+	for _, file := range p.files {
+		// JSON configuration files are not fixed.
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		filename := body.SrcRange.Filename
+		fixed, err := fix.FixHCL2(file.Bytes, filename)
+		if err != nil || bytes.Equal(fixed, file.Bytes) {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Fixable configuration found",
+			Detail: fmt.Sprintf("%s uses deprecated options. Please run `packer fix -write` "+
+				"on it to get your build to run correctly, or `packer fix -diff` to "+
+				"see the changes.", filename),
+		})
+	}
+	return diags
 }
 
 func (p *PackerConfig) InspectConfig(opts packer.InspectConfigOptions) int {
 
 	ui := opts.Ui
+	if opts.Format == "json" {
+		out := &strings.Builder{}
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(p.inspection()); err != nil {
+			ui.Error(fmt.Sprintf("Failed to encode the inspection: %s", err))
+			return 1
+		}
+		ui.Say(strings.TrimSuffix(out.String(), "\n"))
+		return 0
+	}
 	ui.Say("Packer Inspect: HCL2 mode\n")
 	ui.Say(p.printVariables())
 	ui.Say(p.printBuilds())
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/packer-plugin-sdk/template/config"
 	"github.com/hashicorp/packer/hcl2template/addrs"
 	. "github.com/hashicorp/packer/hcl2template/internal"
@@ -662,3 +664,43 @@ func mustVersionConstraints(vs version.Constraints, err error) version.Constrain
 	}
 	return vs
 }
+
+func TestPackerConfig_EvalContext_buildMetadata(t *testing.T) {
+	expr, diags := hclsyntax.ParseExpression([]byte(`packer.build_metadata.vcs.details.commit`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	// The metadata is unknown until it is gathered by the build command.
+	cfg := &PackerConfig{}
+	val, diags := expr.Value(cfg.EvalContext(BuildContext, nil))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if val.IsKnown() {
+		t.Errorf("expected an unknown value, got %#v", val)
+	}
+
+	cfg.envMetadata = map[string]interface{}{
+		"vcs": map[string]interface{}{
+			"type": "git",
+			"details": map[string]interface{}{
+				"commit":                  "abc123",
+				"has_uncommitted_changes": true,
+			},
+		},
+		"cicd": map[string]interface{}(nil),
+	}
+	val, diags = expr.Value(cfg.EvalContext(BuildContext, nil))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if !val.RawEquals(cty.StringVal("abc123")) {
+		t.Errorf("expected the commit, got %#v", val)
+	}
+
+	cicd := cfg.EvalContext(BuildContext, nil).Variables["packer"].GetAttr("build_metadata").GetAttr("cicd")
+	if !cicd.IsNull() {
+		t.Errorf("expected a null cicd metadata outside of CI, got %#v", cicd)
+	}
+}
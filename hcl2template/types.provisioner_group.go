@@ -0,0 +1,244 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	provisionerGroupLabel = "provisioner_group"
+
+	provisionerGroupAccessor = "group"
+)
+
+var provisionerGroupSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "parameters"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: buildProvisionerLabel, LabelNames: []string{"type"}},
+	},
+}
+
+var provisionerGroupUseSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "parameters"},
+	},
+}
+
+// ProvisionerGroupBlock is an ordered list of provisioners that builds
+// include with a `provisioner_group` block of the same name. The provisioners
+// can use the parameters of the group, with their default values set here,
+// as `group.parameters.<name>`:
+//
+//	provisioner_group "baseline" {
+//	  parameters = {
+//	    ssh_port = 22
+//	  }
+//	  provisioner "shell" {
+//	    inline = ["harden --ssh-port ${group.parameters.ssh_port}"]
+//	  }
+//	}
+type ProvisionerGroupBlock struct {
+	// Given name
+	Name string
+
+	// Parameters is the object of default parameters of the group.
+	Parameters hcl.Expression
+
+	provisioners []*hcl.Block
+	block        *hcl.Block
+}
+
+func (p *Parser) decodeProvisionerGroup(block *hcl.Block) (*ProvisionerGroupBlock, hcl.Diagnostics) {
+	content, diags := block.Body.Content(provisionerGroupSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	group := &ProvisionerGroupBlock{
+		Name:  block.Labels[0],
+		block: block,
+	}
+	if attr, ok := content.Attributes["parameters"]; ok {
+		group.Parameters = attr.Expr
+	}
+	group.provisioners = content.Blocks
+
+	if len(group.provisioners) == 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Empty " + provisionerGroupLabel,
+			Detail:   "A " + provisionerGroupLabel + " block must contain at least one " + buildProvisionerLabel + " block.",
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	return group, diags
+}
+
+// decodeProvisionerGroups decodes the top-level provisioner_group blocks of
+// a file. They must be known before builds are decoded, since builds expand
+// them into their provisioners.
+func (p *Parser) decodeProvisionerGroups(file *hcl.File, cfg *PackerConfig) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	content, _ := file.Body.Content(configSchema)
+
+	for _, block := range content.Blocks {
+		if block.Type != provisionerGroupLabel {
+			continue
+		}
+		group, moreDiags := p.decodeProvisionerGroup(block)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if existing, found := cfg.ProvisionerGroups[group.Name]; found {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate " + provisionerGroupLabel + " block",
+				Detail: fmt.Sprintf("This "+provisionerGroupLabel+" block has the "+
+					"same name as a previous block declared at %s. Each "+
+					provisionerGroupLabel+" must have a unique name.",
+					existing.block.DefRange.Ptr()),
+				Subject: block.DefRange.Ptr(),
+			})
+			continue
+		}
+		if cfg.ProvisionerGroups == nil {
+			cfg.ProvisionerGroups = map[string]*ProvisionerGroupBlock{}
+		}
+		cfg.ProvisionerGroups[group.Name] = group
+	}
+
+	return diags
+}
+
+// provisionerGroupUse is the inclusion of a provisioner group in a build,
+// with the parameters set by the build.
+type provisionerGroupUse struct {
+	group      *ProvisionerGroupBlock
+	parameters hcl.Expression
+}
+
+// decodeProvisionerGroupUse decodes a provisioner_group block of a build,
+// and returns the provisioners of the group it references.
+func (p *Parser) decodeProvisionerGroupUse(block *hcl.Block, cfg *PackerConfig, ectx *hcl.EvalContext) ([]*ProvisionerBlock, hcl.Diagnostics) {
+	content, diags := block.Body.Content(provisionerGroupUseSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	group, ok := cfg.ProvisionerGroups[block.Labels[0]]
+	if !ok {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Unknown %s %q", provisionerGroupLabel, block.Labels[0]),
+			Detail:   fmt.Sprintf("Known: %v", listAvailableProvisionerGroupNames(cfg.ProvisionerGroups)),
+			Subject:  block.LabelRanges[0].Ptr(),
+		})
+	}
+
+	use := &provisionerGroupUse{group: group}
+	if attr, ok := content.Attributes["parameters"]; ok {
+		use.parameters = attr.Expr
+	}
+
+	groupCtx, moreDiags := use.evalContext(ectx)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	var provisioners []*ProvisionerBlock
+	for _, block := range group.provisioners {
+		pb, moreDiags := p.decodeProvisioner(block, groupCtx)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		pb.group = use
+		provisioners = append(provisioners, pb)
+	}
+	return provisioners, diags
+}
+
+// evalContext returns a copy of ectx in which the group is available as
+// `group`, with its name and parameters. Parameters set by the build
+// override the default ones of the group.
+func (u *provisionerGroupUse) evalContext(ectx *hcl.EvalContext) (*hcl.EvalContext, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	parameters := map[string]cty.Value{}
+	known := true
+	for _, expr := range []hcl.Expression{u.group.Parameters, u.parameters} {
+		if expr == nil {
+			continue
+		}
+		val, moreDiags := expr.Value(ectx)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if val.IsNull() {
+			continue
+		}
+		if !val.IsKnown() {
+			// For example when datasources are not executed.
+			known = false
+			continue
+		}
+		if !(val.Type().IsObjectType() || val.Type().IsMapType()) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid " + provisionerGroupLabel + " parameters",
+				Detail:      "The parameters of a " + provisionerGroupLabel + " must be an object.",
+				Subject:     expr.Range().Ptr(),
+				Expression:  expr,
+				EvalContext: ectx,
+			})
+			continue
+		}
+		for k, v := range val.AsValueMap() {
+			parameters[k] = v
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// HCL2Provisioner reads the variables of its context directly, so the
+	// group is added to a copy instead of to a child context.
+	variables := make(map[string]cty.Value, len(ectx.Variables)+1)
+	for k, v := range ectx.Variables {
+		variables[k] = v
+	}
+	parametersVal := cty.ObjectVal(parameters)
+	if !known {
+		parametersVal = cty.DynamicVal
+	}
+	variables[provisionerGroupAccessor] = cty.ObjectVal(map[string]cty.Value{
+		"name":       cty.StringVal(u.group.Name),
+		"parameters": parametersVal,
+	})
+	return &hcl.EvalContext{
+		Functions: ectx.Functions,
+		Variables: variables,
+	}, diags
+}
+
+func listAvailableProvisionerGroupNames(groups map[string]*ProvisionerGroupBlock) []string {
+	res := make([]string, 0, len(groups))
+	for name := range groups {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,142 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package hcl2template
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/hashicorp/packer/hcl2template/internal"
+	"github.com/hashicorp/packer/packer"
+)
+
+func TestParse_provisionerGroup(t *testing.T) {
+	defaultParser := getBasicParser()
+
+	groupProvisioner := func(pType, pName, str string) packer.CoreBuildProvisioner {
+		return packer.CoreBuildProvisioner{
+			PType: pType,
+			PName: pName,
+			Provisioner: &HCL2Provisioner{
+				Provisioner: &MockProvisioner{
+					Config: MockConfig{
+						NestedMockConfig: NestedMockConfig{
+							String: str,
+							Tags:   []MockTag{},
+						},
+						NestedSlice: []NestedMockConfig{},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []parseTest{
+		{"builds including a provisioner group",
+			defaultParser,
+			parseTestArgs{"testdata/provisioner_group/basic", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "provisioner_group", "basic"),
+				ProvisionerGroups: map[string]*ProvisionerGroupBlock{
+					"baseline": {Name: "baseline"},
+				},
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204:  {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+					refAWSEBSUbuntu1604: {Type: "amazon-ebs", Name: "ubuntu-1604"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Name: "web",
+						Sources: []SourceUseBlock{
+							{SourceRef: refVBIsoUbuntu1204},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{PType: "shell", PName: "first"},
+							{PType: "shell", PName: "harden-packer"},
+							{PType: "file", PName: "upload"},
+							{PType: "shell", PName: "last"},
+						},
+					},
+					&BuildBlock{
+						Name: "db",
+						Sources: []SourceUseBlock{
+							{SourceRef: refAWSEBSUbuntu1604},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{PType: "shell", PName: "harden-packer"},
+							{PType: "file", PName: "upload"},
+						},
+					},
+				},
+			},
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					BuildName:   "web",
+					Type:        "virtualbox-iso.ubuntu-1204",
+					BuilderType: "virtualbox-iso",
+					Builder:     emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						groupProvisioner("shell", "first", ""),
+						groupProvisioner("shell", "harden-packer", "harden --ssh-port 22"),
+						groupProvisioner("file", "upload", "ubuntu-1204:baseline"),
+						groupProvisioner("shell", "last", ""),
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					BuildName:   "db",
+					Type:        "amazon-ebs.ubuntu-1604",
+					BuilderType: "amazon-ebs",
+					Builder:     emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						groupProvisioner("shell", "harden-packer", "harden --ssh-port 2222"),
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"unknown provisioner group",
+			defaultParser,
+			parseTestArgs{"testdata/provisioner_group/unknown.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "provisioner_group"),
+				ProvisionerGroups: map[string]*ProvisionerGroupBlock{
+					"baseline": {Name: "baseline"},
+				},
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+		{"duplicate provisioner group",
+			defaultParser,
+			parseTestArgs{"testdata/provisioner_group/duplicate.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "provisioner_group"),
+				ProvisionerGroups: map[string]*ProvisionerGroupBlock{
+					"baseline": {Name: "baseline"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+	}
+	testParse(t, tests)
+}
@@ -45,6 +45,16 @@ type SourceUseBlock struct {
 	// content
 	// Body can be expanded by a dynamic tag.
 	Body hcl.Body
+
+	// Preconditions and Postconditions of this source only, checked after
+	// the ones of the build block.
+	Preconditions  []*ConditionBlock
+	Postconditions []*ConditionBlock
+
+	// Each is set when the source block has a for_each argument: it is the
+	// element of the collection this source usage was expanded for, with its
+	// `key` and `value` exposed as `each.key` and `each.value`.
+	Each cty.Value
 }
 
 func (b *SourceUseBlock) name() string {
@@ -66,6 +76,29 @@ func (b *SourceUseBlock) ctyValues() map[string]cty.Value {
 	}
 }
 
+var buildSourceSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "name"},
+		{Name: "for_each"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: buildPreconditionLabel},
+		{Type: buildPostconditionLabel},
+	},
+}
+
+// ctyVariables returns the variables of the source usage, to be added to the
+// eval context of its body and of the blocks run against it.
+func (b *SourceUseBlock) ctyVariables() map[string]cty.Value {
+	variables := map[string]cty.Value{
+		sourcesAccessor: cty.ObjectVal(b.ctyValues()),
+	}
+	if !b.Each.IsNull() {
+		variables[eachAccessor] = b.Each
+	}
+	return variables
+}
+
 // decodeBuildSource reads a used source block from a build:
 //
 //	build {
@@ -73,20 +106,147 @@ func (b *SourceUseBlock) ctyValues() map[string]cty.Value {
 //	    name = "local_name"
 //	  }
 //	}
-func (p *Parser) decodeBuildSource(block *hcl.Block) (SourceUseBlock, hcl.Diagnostics) {
+//
+// A source block with a for_each argument is expanded into one source usage
+// per element of the collection.
+func (p *Parser) decodeBuildSource(block *hcl.Block, ectx *hcl.EvalContext) ([]SourceUseBlock, hcl.Diagnostics) {
 	ref := sourceRefFromString(block.Labels[0])
-	out := SourceUseBlock{SourceRef: ref}
-	var b struct {
-		Name string   `hcl:"name,optional"`
-		Rest hcl.Body `hcl:",remain"`
+	content, rest, diags := block.Body.PartialContent(buildSourceSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	out := SourceUseBlock{SourceRef: ref, Body: rest}
+
+	for _, block := range content.Blocks {
+		c, moreDiags := p.decodeConditionBlock(block)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if block.Type == buildPreconditionLabel {
+			out.Preconditions = append(out.Preconditions, c)
+		} else {
+			out.Postconditions = append(out.Postconditions, c)
+		}
 	}
-	diags := gohcl.DecodeBody(block.Body, nil, &b)
 	if diags.HasErrors() {
-		return out, diags
+		return nil, diags
+	}
+
+	nameAttr, hasName := content.Attributes["name"]
+	forEachAttr, hasForEach := content.Attributes["for_each"]
+	if !hasForEach {
+		if hasName {
+			diags = append(diags, gohcl.DecodeExpression(nameAttr.Expr, nil, &out.LocalName)...)
+		}
+		return []SourceUseBlock{out}, diags
+	}
+
+	elements, moreDiags := decodeForEach(forEachAttr.Expr, ectx)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
 	}
-	out.LocalName = b.Name
-	out.Body = b.Rest
-	return out, nil
+
+	var res []SourceUseBlock
+	names := map[string]bool{}
+	for _, each := range elements {
+		src := out
+		src.Each = each
+
+		key := each.GetAttr("key")
+		if !key.IsKnown() {
+			// The collection is not known yet, for example when datasources
+			// are not executed; the source is decoded once with unknown
+			// values to be validated.
+			return []SourceUseBlock{src}, diags
+		}
+
+		src.LocalName = fmt.Sprintf("%s-%s", ref.Name, key.AsString())
+		if hasName {
+			nameCtx := ectx.NewChild()
+			nameCtx.Variables = map[string]cty.Value{eachAccessor: each}
+			moreDiags := gohcl.DecodeExpression(nameAttr.Expr, nameCtx, &src.LocalName)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+		}
+		if names[src.LocalName] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate " + sourceLabel + " name",
+				Detail: fmt.Sprintf("The for_each argument of %s produces the name %q more than once; "+
+					"use each.key in its name to tell the sources apart.", ref.String(), src.LocalName),
+				Subject: block.DefRange.Ptr(),
+			})
+			continue
+		}
+		names[src.LocalName] = true
+		res = append(res, src)
+	}
+	return res, diags
+}
+
+// decodeForEach evaluates a for_each argument, that must be a map or a set of
+// strings, and returns an `each` object for every element of the collection,
+// with its key and value. Elements of a set are their own key.
+//
+// When the collection is not known yet, a single `each` object with unknown
+// values is returned.
+func decodeForEach(expr hcl.Expression, ectx *hcl.EvalContext) ([]cty.Value, hcl.Diagnostics) {
+	const errSummary = "Invalid for_each argument"
+
+	val, diags := expr.Value(ectx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ty := val.Type()
+	isSet := ty.IsSetType()
+	switch {
+	case val.IsNull():
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  errSummary,
+			Detail:   "The for_each argument must not be null.",
+			Subject:  expr.Range().Ptr(),
+		})
+	case !ty.IsMapType() && !ty.IsObjectType() && !(isSet && ty.ElementType() == cty.String) && ty != cty.DynamicPseudoType:
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  errSummary,
+			Detail: fmt.Sprintf("The for_each argument must be a map, or a set of strings, got %s. "+
+				"A list of strings can be converted to a set with convert(list, set(string)).", ty.FriendlyName()),
+			Subject: expr.Range().Ptr(),
+		})
+	case !val.IsWhollyKnown():
+		return []cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"key":   cty.UnknownVal(cty.String),
+			"value": cty.DynamicVal,
+		})}, diags
+	}
+
+	var res []cty.Value
+	for it := val.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+		if isSet {
+			key = value
+		}
+		if key.IsNull() {
+			return nil, append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  errSummary,
+				Detail:   "The for_each set must not contain null values.",
+				Subject:  expr.Range().Ptr(),
+			})
+		}
+		res = append(res, cty.ObjectVal(map[string]cty.Value{
+			"key":   key,
+			"value": value,
+		}))
+	}
+	return res, diags
 }
 
 func (p *Parser) decodeSource(block *hcl.Block) (SourceBlock, hcl.Diagnostics) {
@@ -113,9 +273,15 @@ func (cfg *PackerConfig) startBuilder(source SourceUseBlock, ectx *hcl.EvalConte
 		return builder, diags, nil
 	}
 
-	body := source.Body
+	body, moreDiags := cfg.withCommunicator(source.Body)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return builder, diags, nil
+	}
 	// Add known values to source accessor in eval context.
-	ectx.Variables[sourcesAccessor] = cty.ObjectVal(source.ctyValues())
+	for k, v := range source.ctyVariables() {
+		ectx.Variables[k] = v
+	}
 
 	decoded, moreDiags := decodeHCL2Spec(body, ectx, builder)
 	diags = append(diags, moreDiags...)
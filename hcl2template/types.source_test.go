@@ -8,7 +8,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/packer/builder/null"
+	. "github.com/hashicorp/packer/hcl2template/internal"
 	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestParse_source(t *testing.T) {
@@ -153,3 +155,179 @@ func TestParse_source(t *testing.T) {
 	}
 	testParse(t, tests)
 }
+
+func TestParse_sourceForEach(t *testing.T) {
+	defaultParser := getBasicParser()
+
+	imageType := cty.Object(map[string]cty.Type{"os": cty.String, "region": cty.String})
+	image := func(os, region string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"os": cty.StringVal(os), "region": cty.StringVal(region)})
+	}
+	each := func(key string, value cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"key": cty.StringVal(key), "value": value})
+	}
+	refVBIsoBase := SourceRef{Type: "virtualbox-iso", Name: "base"}
+	forEachBuilder := func(os string) *MockBuilder {
+		return &MockBuilder{
+			Config: MockConfig{
+				NestedMockConfig: NestedMockConfig{
+					String: os,
+					Int:    42,
+					Tags:   []MockTag{},
+				},
+				NestedSlice: []NestedMockConfig{},
+			},
+		}
+	}
+	forEachProvisioner := func(name string) []packer.CoreBuildProvisioner {
+		return []packer.CoreBuildProvisioner{
+			{
+				PType: "shell",
+				Provisioner: &HCL2Provisioner{
+					Provisioner: &MockProvisioner{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{
+								String: name,
+								Tags:   []MockTag{},
+							},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []parseTest{
+		{"sources expanded with for_each",
+			defaultParser,
+			parseTestArgs{"testdata/sources/for_each.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "sources"),
+				InputVariables: Variables{
+					"images": &Variable{
+						Name: "images",
+						Type: cty.Map(imageType),
+						Values: []VariableAssignment{{
+							From: "default",
+							Value: cty.MapVal(map[string]cty.Value{
+								"focal": image("ubuntu-20.04", "us-east-1"),
+								"jammy": image("ubuntu-22.04", "eu-west-1"),
+							}),
+						}},
+					},
+				},
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoBase: {Type: "virtualbox-iso", Name: "base"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{
+								SourceRef: refVBIsoBase,
+								LocalName: "ubuntu-focal",
+								Each:      each("focal", image("ubuntu-20.04", "us-east-1")),
+							},
+							{
+								SourceRef: refVBIsoBase,
+								LocalName: "ubuntu-jammy",
+								Each:      each("jammy", image("ubuntu-22.04", "eu-west-1")),
+							},
+							{
+								SourceRef: refVBIsoBase,
+								LocalName: "base-alpine",
+								Each:      each("alpine", cty.StringVal("alpine")),
+							},
+							{
+								SourceRef: refVBIsoBase,
+								LocalName: "base-debian",
+								Each:      each("debian", cty.StringVal("debian")),
+							},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{
+								PType: "shell",
+								OnlyExcept: OnlyExcept{
+									Only: []string{"virtualbox-iso.ubuntu-jammy", "virtualbox-iso.base-debian"},
+								},
+							},
+						},
+					},
+				},
+			},
+			false, false,
+			[]*packer.CoreBuild{
+				&packer.CoreBuild{
+					Type:           "virtualbox-iso.ubuntu-focal",
+					BuilderType:    "virtualbox-iso",
+					Builder:        forEachBuilder("ubuntu-20.04"),
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					Type:           "virtualbox-iso.ubuntu-jammy",
+					BuilderType:    "virtualbox-iso",
+					Builder:        forEachBuilder("ubuntu-22.04"),
+					Provisioners:   forEachProvisioner("ubuntu-jammy"),
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					Type:           "virtualbox-iso.base-alpine",
+					BuilderType:    "virtualbox-iso",
+					Builder:        forEachBuilder(""),
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+				&packer.CoreBuild{
+					Type:           "virtualbox-iso.base-debian",
+					BuilderType:    "virtualbox-iso",
+					Builder:        forEachBuilder(""),
+					Provisioners:   forEachProvisioner("base-debian"),
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+					Prepared:       true,
+					SensitiveVars:  []string{},
+				},
+			},
+			false,
+			nil,
+		},
+		{"for_each list",
+			defaultParser,
+			parseTestArgs{"testdata/sources/for_each_list.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "sources"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoBase: {Type: "virtualbox-iso", Name: "base"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+		{"for_each producing duplicate names",
+			defaultParser,
+			parseTestArgs{"testdata/sources/for_each_duplicate.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "sources"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoBase: {Type: "virtualbox-iso", Name: "base"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+			nil,
+		},
+	}
+	testParse(t, tests)
+}
@@ -6,6 +6,7 @@ package hcl2template
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -98,24 +99,15 @@ func (v *Variable) GoString() string {
 	return b.String()
 }
 
-// validateValue ensures that all of the configured custom validations for a
-// variable value are passing.
-func (v *Variable) validateValue(val VariableAssignment) (diags hcl.Diagnostics) {
-	if len(v.Validations) == 0 {
+// validateValue ensures that the given custom validations for a variable value
+// are passing.
+func (v *Variable) validateValue(val VariableAssignment, hclCtx *hcl.EvalContext, validations []*VariableValidation) (diags hcl.Diagnostics) {
+	if len(validations) == 0 {
 		log.Printf("[TRACE] validateValue: not active for %s, so skipping", v.Name)
 		return nil
 	}
 
-	hclCtx := &hcl.EvalContext{
-		Variables: map[string]cty.Value{
-			"var": cty.ObjectVal(map[string]cty.Value{
-				v.Name: val.Value,
-			}),
-		},
-		Functions: Functions(""),
-	}
-
-	for _, validation := range v.Validations {
+	for _, validation := range validations {
 		const errInvalidCondition = "Invalid variable validation result"
 
 		result, moreDiags := validation.Condition.Value(hclCtx)
@@ -179,20 +171,41 @@ func (v *Variable) Value() cty.Value {
 }
 
 // ValidateValue tells if the selected value for the Variable is valid according
-// to its validation settings.
+// to its validation settings. Validation rules referencing other input
+// variables or local values are not evaluated here, but when the
+// configuration is initialized.
 func (v *Variable) ValidateValue() hcl.Diagnostics {
 	if len(v.Values) == 0 {
-		return hcl.Diagnostics{&hcl.Diagnostic{
-			Severity: hcl.DiagError,
-			Summary:  fmt.Sprintf("Unset variable %q", v.Name),
-			Detail: "A used variable must be set or have a default value; see " +
-				"https://packer.io/docs/templates/hcl_templates/syntax for " +
-				"details.",
-			Context: v.Range.Ptr(),
-		}}
+		return v.unsetDiags()
+	}
+
+	var validations []*VariableValidation
+	for _, validation := range v.Validations {
+		if len(validation.references) == 0 {
+			validations = append(validations, validation)
+		}
+	}
+	hclCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(map[string]cty.Value{
+				v.Name: v.Value(),
+			}),
+		},
+		Functions: Functions(""),
 	}
 
-	return v.validateValue(v.Values[len(v.Values)-1])
+	return v.validateValue(v.Values[len(v.Values)-1], hclCtx, validations)
+}
+
+func (v *Variable) unsetDiags() hcl.Diagnostics {
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("Unset variable %q", v.Name),
+		Detail: "A used variable must be set or have a default value; see " +
+			"https://packer.io/docs/templates/hcl_templates/syntax for " +
+			"details.",
+		Context: v.Range.Ptr(),
+	}}
 }
 
 type Variables map[string]*Variable
@@ -214,14 +227,125 @@ func (variables Variables) Values() map[string]cty.Value {
 	return res
 }
 
-func (variables Variables) ValidateValues() hcl.Diagnostics {
+// validateInputVariables ensures that the values of the input variables pass
+// their validation rules, which can refer to other input variables and to
+// local values.
+//
+// Every rule is evaluated against the collected values of all the variables.
+// Rules whose operands are unset or unknown are skipped; so are rules
+// referring to local values that could not be evaluated, for example because
+// they refer to each other, which is reported when evaluating them.
+//
+// Rules referencing local values are only evaluated when withLocals is set,
+// which is done once the local values are evaluated.
+func (cfg *PackerConfig) validateInputVariables(withLocals bool) hcl.Diagnostics {
 	var diags hcl.Diagnostics
-	for _, v := range variables {
-		diags = append(diags, v.ValidateValue()...)
+
+	hclCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(cfg.InputVariables.Values()),
+		},
+		Functions: Functions(""),
+	}
+	if withLocals {
+		hclCtx.Variables["local"] = cty.ObjectVal(cfg.LocalVariables.Values())
 	}
+
+	localBlocks := map[string]*LocalBlock{}
+	for _, local := range cfg.LocalBlocks {
+		localBlocks[local.LocalName] = local
+	}
+
+	names := cfg.InputVariables.Keys()
+	sort.Strings(names)
+	for _, name := range names {
+		v := cfg.InputVariables[name]
+		if len(v.Values) == 0 {
+			if !withLocals {
+				diags = append(diags, v.unsetDiags()...)
+			}
+			continue
+		}
+
+		var validations []*VariableValidation
+		for _, validation := range v.Validations {
+			deps := getValidationDependencies(name, validation, localBlocks)
+			if (len(deps.locals) > 0) != withLocals {
+				continue
+			}
+			evaluated := true
+			for _, local := range deps.locals {
+				_, ok := cfg.LocalVariables[local]
+				if _, exists := localBlocks[local]; exists && !ok {
+					evaluated = false
+				}
+			}
+			if !evaluated {
+				log.Printf("[TRACE] validateInputVariables: %s rule %s refers to local values that were not evaluated, so skipping", name, validation.DeclRange)
+				continue
+			}
+			// Unset variables have an unknown value, which makes the
+			// condition unknown and the rule skipped.
+			validations = append(validations, validation)
+		}
+
+		diags = append(diags, v.validateValue(v.Values[len(v.Values)-1], hclCtx, validations)...)
+	}
+
 	return diags
 }
 
+// validationDependencies are the other input variables and the local values
+// that a validation rule refers to, directly or through local values.
+type validationDependencies struct {
+	variables []string
+	locals    []string
+}
+
+func getValidationDependencies(varName string, validation *VariableValidation, localBlocks map[string]*LocalBlock) validationDependencies {
+	var deps validationDependencies
+	seen := map[string]bool{}
+
+	var visit func(refs []addrs.Referenceable)
+	visit = func(refs []addrs.Referenceable) {
+		for _, ref := range refs {
+			if seen[ref.String()] {
+				continue
+			}
+			seen[ref.String()] = true
+
+			switch ref := ref.(type) {
+			case addrs.InputVariable:
+				if ref.Name != varName {
+					deps.variables = append(deps.variables, ref.Name)
+				}
+			case addrs.LocalValue:
+				deps.locals = append(deps.locals, ref.Name)
+				if local, ok := localBlocks[ref.Name]; ok {
+					visit(expressionReferences(local.Expr))
+				}
+			}
+		}
+	}
+	visit(validation.references)
+
+	return deps
+}
+
+// expressionReferences returns the input variables and local values that an
+// expression refers to.
+func expressionReferences(expr hcl.Expression) []addrs.Referenceable {
+	var refs []addrs.Referenceable
+	for _, traversal := range expr.Variables() {
+		ref, diags := addrs.ParseRef(traversal)
+		if diags.HasErrors() {
+			continue
+		}
+		refs = append(refs, ref.Subject)
+	}
+	return refs
+}
+
 // decodeVariable decodes a variable key and value into Variables
 func (variables *Variables) decodeVariable(key string, attr *hcl.Attribute, ectx *hcl.EvalContext) hcl.Diagnostics {
 	var diags hcl.Diagnostics
@@ -443,8 +567,9 @@ var variableValidationBlockSchema = &hcl.BodySchema{
 // for a particular input variable, given as a "validation" block inside
 // a "variable" block.
 type VariableValidation struct {
-	// Condition is an expression that refers to the variable being tested and
-	// contains no other references. The expression must return true to
+	// Condition is an expression that refers to the variable being tested, and
+	// optionally to other input variables and local values. The expression
+	// must return true to
 	// indicate that the value is valid or false to indicate that it is
 	// invalid. If the expression produces an error, that's considered a bug in
 	// the block defining the validation rule, not an error in the caller.
@@ -458,6 +583,10 @@ type VariableValidation struct {
 	ErrorMessage string
 
 	DeclRange hcl.Range
+
+	// references are the other input variables and the local values that
+	// Condition refers to.
+	references []addrs.Referenceable
 }
 
 func decodeVariableValidationBlock(varName string, block *hcl.Block) (*VariableValidation, hcl.Diagnostics) {
@@ -472,19 +601,25 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block) (*VariableV
 	if attr, exists := content.Attributes["condition"]; exists {
 		vv.Condition = attr.Expr
 
-		// The validation condition must refer to the variable itself and
-		// nothing else; to ensure that the variable declaration can't create
-		// additional edges in the dependency graph.
+		// The validation condition must refer to the variable itself, and can
+		// refer to other input variables and local values; these conditions
+		// are evaluated once all values are known.
 		goodRefs := 0
 		for _, traversal := range vv.Condition.Variables() {
 
 			ref, moreDiags := addrs.ParseRef(traversal)
 			if !moreDiags.HasErrors() {
-				if addr, ok := ref.Subject.(addrs.InputVariable); ok {
+				switch addr := ref.Subject.(type) {
+				case addrs.InputVariable:
 					if addr.Name == varName {
 						goodRefs++
 						continue // Reference is valid
 					}
+					vv.references = append(vv.references, addr)
+					continue
+				case addrs.LocalValue:
+					vv.references = append(vv.references, addr)
+					continue
 				}
 			}
 
@@ -492,7 +627,7 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block) (*VariableV
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid reference in variable validation",
-				Detail:   fmt.Sprintf("The condition for variable %q can only refer to the variable itself, using var.%s.", varName, varName),
+				Detail:   fmt.Sprintf("The condition for variable %q can only refer to input variables and local values, using var.<name> or local.<name>.", varName),
 				Subject:  traversal.SourceRange().Ptr(),
 			})
 		}
@@ -6,6 +6,7 @@ package hcl2template
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -1069,3 +1070,73 @@ func stringListVal(strings ...string) cty.Value {
 	}
 	return list
 }
+
+func TestPackerConfig_validateInputVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		argVars   map[string]string
+		wantDiags []string
+	}{
+		{"valid values",
+			"testdata/variables/validation/cross_references.pkr.hcl",
+			nil,
+			nil,
+		},
+		{"variable referencing another variable",
+			"testdata/variables/validation/cross_references.pkr.hcl",
+			map[string]string{"disk_size": "5"},
+			[]string{"The disk_size value must be larger than base_image_size."},
+		},
+		{"rules are evaluated against the values of invalid variables",
+			"testdata/variables/validation/cross_references.pkr.hcl",
+			map[string]string{"disk_size": "5", "base_image_size": "-1"},
+			[]string{"The base_image_size value must be positive."},
+		},
+		{"variable referencing locals",
+			"testdata/variables/validation/cross_references.pkr.hcl",
+			map[string]string{"region": "mars-1"},
+			[]string{"The region value must be an allowed region."},
+		},
+		{"second rule referencing locals",
+			"testdata/variables/validation/cross_references.pkr.hcl",
+			map[string]string{"region": "cn-north-1"},
+			[]string{"The region value must not be a China region."},
+		},
+		{"variables referencing each other",
+			"testdata/variables/validation/min_max.pkr.hcl",
+			nil,
+			nil,
+		},
+		{"invalid variables referencing each other",
+			"testdata/variables/validation/min_max.pkr.hcl",
+			map[string]string{"min_size": "3"},
+			[]string{
+				"The max_size value must be greater than min_size.",
+				"The min_size value must be lower than max_size.",
+			},
+		},
+		{"rule referencing a cycle of locals",
+			"testdata/variables/validation/cycle.pkr.hcl",
+			nil,
+			[]string{"Cycle: local."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, diags := getBasicParser().Parse(tt.file, nil, tt.argVars)
+			if diags.HasErrors() {
+				t.Fatalf("Parse: %s", diags)
+			}
+			diags = cfg.Initialize(packer.InitializeOptions{})
+			if len(diags) != len(tt.wantDiags) {
+				t.Fatalf("expected %d diagnostics, got %s", len(tt.wantDiags), diags)
+			}
+			for i, want := range tt.wantDiags {
+				if !strings.Contains(diags[i].Detail, want) {
+					t.Errorf("expected %q in diagnostic %q", want, diags[i].Detail)
+				}
+			}
+		})
+	}
+}
@@ -182,6 +182,32 @@ func ConvertPluginConfigValueToHCLValue(v interface{}) (cty.Value, error) {
 		} else {
 			buildValue = cty.ListVal(vals)
 		}
+	case map[string]interface{}:
+		if v == nil {
+			buildValue = cty.NullVal(cty.DynamicPseudoType)
+			break
+		}
+		vals := make(map[string]cty.Value, len(v))
+		for k, ev := range v {
+			val, err := ConvertPluginConfigValueToHCLValue(ev)
+			if err != nil {
+				return cty.Value{}, err
+			}
+			vals[k] = val
+		}
+		buildValue = cty.ObjectVal(vals)
+	case map[interface{}]interface{}:
+		vals := make(map[string]cty.Value, len(v))
+		for k, ev := range v {
+			val, err := ConvertPluginConfigValueToHCLValue(ev)
+			if err != nil {
+				return cty.Value{}, err
+			}
+			vals[fmt.Sprint(k)] = val
+		}
+		buildValue = cty.ObjectVal(vals)
+	case nil:
+		buildValue = cty.NullVal(cty.DynamicPseudoType)
 	default:
 		return cty.Value{}, fmt.Errorf("unhandled buildvar type: %T", v)
 	}
@@ -193,9 +219,16 @@ func ConvertPluginConfigValueToHCLValue(v interface{}) (cty.Value, error) {
 //
 // This will only work on finite, expanded, HCL bodies.
 func GetVarsByType(block *hcl.Block, topLevelLabels ...string) []hcl.Traversal {
+	return FilterTraversalsByType(bodyTraversals(block.Body), topLevelLabels...)
+}
+
+// bodyTraversals returns the traversals of all the expressions of a body,
+// including the ones of its nested blocks when the body is native syntax.
+func bodyTraversals(body hcl.Body) []hcl.Traversal {
 	var travs []hcl.Traversal
 
-	switch body := block.Body.(type) {
+	switch body := body.(type) {
+	case nil:
 	case *hclsyntax.Body:
 		travs = getVarsByTypeForHCLSyntaxBody(body)
 	default:
@@ -205,7 +238,7 @@ func GetVarsByType(block *hcl.Block, topLevelLabels ...string) []hcl.Traversal {
 		}
 	}
 
-	return FilterTraversalsByType(travs, topLevelLabels...)
+	return travs
 }
 
 // FilterTraversalsByType lets the caller filter the traversals per top-level type.
@@ -0,0 +1,108 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package dag
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// ConcurrentWalk calls fn on every vertex of the graph, concurrently. A vertex
+// is walked as soon as fn has succeeded on all the targets of its edges, i.e.
+// its dependencies, so independent vertices are walked in parallel.
+//
+// At most parallelism calls to fn run at the same time; a parallelism lower
+// than 1 does not bound them. Once fn returns an error, no more vertices are
+// walked, and ConcurrentWalk returns when the calls in progress are done.
+//
+// The diagnostics returned by fn are ordered by vertex name, so that the
+// result does not depend on scheduling.
+func (g *AcyclicGraph) ConcurrentWalk(parallelism int, fn WalkFunc) hcl.Diagnostics {
+	vertices := g.Vertices()
+	sort.SliceStable(vertices, func(i, j int) bool {
+		return VertexName(vertices[i]) < VertexName(vertices[j])
+	})
+	if parallelism < 1 {
+		parallelism = len(vertices)
+	}
+
+	// pending counts the dependencies of each vertex that were not walked
+	// yet, a vertex is ready once it reaches zero.
+	pending := make(map[interface{}]int, len(vertices))
+	order := make(map[interface{}]int, len(vertices))
+	var ready []Vertex
+	for i, v := range vertices {
+		order[hashcode(v)] = i
+		pending[hashcode(v)] = g.downEdgesNoCopy(v).Len()
+		if pending[hashcode(v)] == 0 {
+			ready = append(ready, v)
+		}
+	}
+
+	type result struct {
+		vertex Vertex
+		diags  hcl.Diagnostics
+	}
+	results := make(chan result)
+	walked := make(map[interface{}]hcl.Diagnostics, len(vertices))
+	running := 0
+	failed := false
+
+	for {
+		for !failed && len(ready) > 0 && running < parallelism {
+			v := ready[0]
+			ready = ready[1:]
+			running++
+			go func(v Vertex) {
+				results <- result{vertex: v, diags: fn(v)}
+			}(v)
+		}
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		walked[hashcode(res.vertex)] = res.diags
+		if res.diags.HasErrors() {
+			failed = true
+			continue
+		}
+
+		for _, raw := range g.upEdgesNoCopy(res.vertex) {
+			u := raw.(Vertex)
+			pending[hashcode(u)]--
+			if pending[hashcode(u)] == 0 {
+				ready = append(ready, u)
+			}
+		}
+		// Keep starting ready vertices in name order.
+		sort.SliceStable(ready, func(i, j int) bool {
+			return order[hashcode(ready[i])] < order[hashcode(ready[j])]
+		})
+	}
+
+	var diags hcl.Diagnostics
+	for _, v := range vertices {
+		diags = diags.Extend(walked[hashcode(v)])
+	}
+
+	if !failed && len(walked) < len(vertices) {
+		var unwalked []string
+		for _, v := range vertices {
+			if _, ok := walked[hashcode(v)]; !ok {
+				unwalked = append(unwalked, VertexName(v))
+			}
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cycle detected in dependency graph",
+			Detail:   fmt.Sprintf("The following vertices could not be walked as they depend on each other: %v", unwalked),
+		})
+	}
+
+	return diags
+}
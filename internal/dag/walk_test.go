@@ -0,0 +1,134 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package dag
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestAcyclicGraphConcurrentWalk(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Add(4)
+	g.Connect(BasicEdge(3, 1))
+	g.Connect(BasicEdge(3, 2))
+	g.Connect(BasicEdge(4, 3))
+
+	var lock sync.Mutex
+	done := map[Vertex]bool{}
+	diags := g.ConcurrentWalk(0, func(v Vertex) hcl.Diagnostics {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, dep := range g.downEdgesNoCopy(v) {
+			if !done[dep] {
+				t.Errorf("%v walked before its dependency %v", v, dep)
+			}
+		}
+		done[v] = true
+		return nil
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	if len(done) != 4 {
+		t.Fatalf("expected 4 walked vertices, got %v", done)
+	}
+}
+
+func TestAcyclicGraphConcurrentWalk_parallelism(t *testing.T) {
+	var g AcyclicGraph
+	for i := 0; i < 10; i++ {
+		g.Add(i)
+	}
+
+	var running, maxRunning int32
+	diags := g.ConcurrentWalk(3, func(v Vertex) hcl.Diagnostics {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	if maxRunning < 2 || maxRunning > 3 {
+		t.Fatalf("expected 2 or 3 concurrent walks, got %d", maxRunning)
+	}
+}
+
+func TestAcyclicGraphConcurrentWalk_error(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []string{"a", "b", "c", "d"} {
+		g.Add(v)
+	}
+	// d depends on a, which fails.
+	g.Connect(BasicEdge("d", "a"))
+
+	var lock sync.Mutex
+	walked := map[Vertex]bool{}
+	diags := g.ConcurrentWalk(1, func(v Vertex) hcl.Diagnostics {
+		lock.Lock()
+		walked[v] = true
+		lock.Unlock()
+		if v == "a" || v == "c" {
+			return hcl.Diagnostics{{Severity: hcl.DiagError, Summary: fmt.Sprintf("%v failed", v)}}
+		}
+		return nil
+	})
+
+	if walked["d"] {
+		t.Error("d should not be walked as its dependency failed")
+	}
+	if walked["b"] || walked["c"] {
+		t.Error("no vertex should be walked after the first error")
+	}
+	if len(diags) != 1 || diags[0].Summary != "a failed" {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+}
+
+func TestAcyclicGraphConcurrentWalk_diagsOrder(t *testing.T) {
+	var g AcyclicGraph
+	for _, v := range []string{"c", "a", "b"} {
+		g.Add(v)
+	}
+
+	for i := 0; i < 20; i++ {
+		diags := g.ConcurrentWalk(0, func(v Vertex) hcl.Diagnostics {
+			time.Sleep(time.Duration(len(v.(string))) * time.Millisecond)
+			return hcl.Diagnostics{{Severity: hcl.DiagWarning, Summary: v.(string)}}
+		})
+		if len(diags) != 3 || diags[0].Summary != "a" || diags[1].Summary != "b" || diags[2].Summary != "c" {
+			t.Fatalf("diags are not ordered by vertex name: %s", diags)
+		}
+	}
+}
+
+func TestAcyclicGraphConcurrentWalk_cycle(t *testing.T) {
+	var g AcyclicGraph
+	g.Add(1)
+	g.Add(2)
+	g.Add(3)
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 1))
+
+	diags := g.ConcurrentWalk(0, func(v Vertex) hcl.Diagnostics { return nil })
+	if !diags.HasErrors() {
+		t.Fatal("should error")
+	}
+}
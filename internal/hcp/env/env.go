@@ -47,6 +47,12 @@ func HasPackerRegistryBucket() bool {
 	return hasEnvVar(HCPPackerBucket)
 }
 
+// HasPackerRegistryLocation returns true if a self-hosted registry is
+// configured with HCP_PACKER_REGISTRY_LOCATION.
+func HasPackerRegistryLocation() bool {
+	return hasEnvVar(HCPPackerRegistryLocation)
+}
+
 func hasEnvVar(varName string) bool {
 	val, ok := os.LookupEnv(varName)
 	if !ok {
@@ -15,4 +15,6 @@ const (
 	HCPPackerRegistry          = "HCP_PACKER_REGISTRY"
 	HCPPackerBucket            = "HCP_PACKER_BUCKET_NAME"
 	HCPPackerBuildFingerprint  = "HCP_PACKER_BUILD_FINGERPRINT"
+	HCPPackerRegistryLocation  = "HCP_PACKER_REGISTRY_LOCATION"
+	HCPPackerRegistryToken     = "HCP_PACKER_REGISTRY_TOKEN"
 )
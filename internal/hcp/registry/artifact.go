@@ -13,6 +13,9 @@ type registryArtifact struct {
 	BucketName string
 	VersionID  string
 	BuildName  string
+	// Location is the location of the self-hosted registry the metadata was
+	// published to, empty for HCP Packer.
+	Location string
 }
 
 func (a *registryArtifact) BuilderId() string {
@@ -28,6 +31,9 @@ func (a *registryArtifact) Files() []string {
 }
 
 func (a *registryArtifact) String() string {
+	if a.Location != "" {
+		return fmt.Sprintf("Published metadata to registry %s: %s/versions/%s", a.Location, a.BucketName, a.VersionID)
+	}
 	return fmt.Sprintf("Published metadata to HCP Packer registry packer/%s/versions/%s", a.BucketName, a.VersionID)
 }
 
@@ -153,6 +153,19 @@ func (h *HCLRegistry) InjectEnforcedProvisioners(builds []*packer.CoreBuild) hcl
 }
 
 func NewHCLRegistry(config *hcl2template.PackerConfig, ui sdkpacker.Ui) (*HCLRegistry, hcl.Diagnostics) {
+	registry, diags := newHCLRegistry(config, ui, withHCPAuthentication)
+	if registry != nil {
+		ui.Say(fmt.Sprintf("Tracking build on HCP Packer with fingerprint %q", registry.bucket.Version.Fingerprint))
+	}
+	return registry, diags
+}
+
+// newHCLRegistry configures the bucket of the config and registers its
+// builds. opts are applied before the configuration of the bucket from the
+// config.
+func newHCLRegistry(
+	config *hcl2template.PackerConfig, ui sdkpacker.Ui, opts ...bucketConfigurationOpts,
+) (*HCLRegistry, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	if len(config.Builds) > 1 {
 		diags = append(diags, &hcl.Diagnostic{
@@ -206,10 +219,12 @@ func NewHCLRegistry(config *hcl2template.PackerConfig, ui sdkpacker.Ui) (*HCLReg
 
 	bucket, bucketDiags := createConfiguredBucket(
 		config.Basedir,
-		withPackerEnvConfiguration,
-		withHCLBucketConfiguration,
-		withDeprecatedDatasourceConfiguration(vals, ui),
-		withDatasourceConfiguration(vals),
+		append(opts,
+			withPackerEnvConfiguration,
+			withHCLBucketConfiguration,
+			withDeprecatedDatasourceConfiguration(vals, ui),
+			withDatasourceConfiguration(vals),
+		)...,
 	)
 	if bucketDiags != nil {
 		diags = append(diags, bucketDiags...)
@@ -227,8 +242,6 @@ func NewHCLRegistry(config *hcl2template.PackerConfig, ui sdkpacker.Ui) (*HCLReg
 		buildNames:    map[string]struct{}{},
 	}
 
-	ui.Say(fmt.Sprintf("Tracking build on HCP Packer with fingerprint %q", bucket.Version.Fingerprint))
-
 	return registry, diags.Extend(registry.registerAllComponents())
 }
 
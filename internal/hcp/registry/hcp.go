@@ -49,8 +49,8 @@ func IsHCPEnabled(cfg packer.Handler) bool {
 		}
 	}
 
-	// HCP_PACKER_BUCKET_NAME is set or HCP_PACKER_REGISTRY not toggled off
-	if mode == HCPConfigUnset && (env.HasPackerRegistryBucket() || env.IsHCPExplicitelyEnabled()) {
+	// HCP_PACKER_BUCKET_NAME or HCP_PACKER_REGISTRY_LOCATION is set or HCP_PACKER_REGISTRY not toggled off
+	if mode == HCPConfigUnset && (env.HasPackerRegistryBucket() || env.HasPackerRegistryLocation() || env.IsHCPExplicitelyEnabled()) {
 		mode = HCPEnvEnabled
 	}
 
@@ -63,21 +63,6 @@ func IsHCPEnabled(cfg packer.Handler) bool {
 func createConfiguredBucket(templateDir string, opts ...bucketConfigurationOpts) (*Bucket, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
-	hasAuth, err := env.HasHCPAuth()
-	if err != nil {
-		diags = append(diags, &hcl.Diagnostic{
-			Summary:  "HCP authentication check failed",
-			Detail:   fmt.Sprintf("Failed to check for HCP authentication, error: %s", err.Error()),
-			Severity: hcl.DiagError,
-		})
-	} else if !hasAuth {
-		diags = append(diags, &hcl.Diagnostic{
-			Summary:  "HCP authentication information required",
-			Detail:   fmt.Sprintf("HCP Authentication not configured, either set an HCP Client ID and secret using the environment variables %s and %s, place an HCP credential file in the default path (%s), or at a different path specified in the %s environment variable.", env.HCPClientID, env.HCPClientSecret, env.HCPDefaultCredFilePath, env.HCPCredFile),
-			Severity: hcl.DiagError,
-		})
-	}
-
 	bucket := NewBucketWithVersion()
 
 	for _, opt := range opts {
@@ -98,7 +83,7 @@ func createConfiguredBucket(templateDir string, opts ...bucketConfigurationOpts)
 		})
 	}
 
-	err = bucket.Version.Initialize()
+	err := bucket.Version.Initialize()
 	if err != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Summary: "Version initialization failed",
@@ -110,6 +95,27 @@ func createConfiguredBucket(templateDir string, opts ...bucketConfigurationOpts)
 	return bucket, diags
 }
 
+// withHCPAuthentication checks that credentials are available for connecting
+// to HCP.
+func withHCPAuthentication(*Bucket) hcl.Diagnostics {
+	hasAuth, err := env.HasHCPAuth()
+	if err != nil {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Summary:  "HCP authentication check failed",
+			Detail:   fmt.Sprintf("Failed to check for HCP authentication, error: %s", err.Error()),
+			Severity: hcl.DiagError,
+		}}
+	}
+	if !hasAuth {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Summary:  "HCP authentication information required",
+			Detail:   fmt.Sprintf("HCP Authentication not configured, either set an HCP Client ID and secret using the environment variables %s and %s, place an HCP credential file in the default path (%s), or at a different path specified in the %s environment variable.", env.HCPClientID, env.HCPClientSecret, env.HCPDefaultCredFilePath, env.HCPCredFile),
+			Severity: hcl.DiagError,
+		}}
+	}
+	return nil
+}
+
 func withPackerEnvConfiguration(bucket *Bucket) hcl.Diagnostics {
 	// Add default values for Packer settings configured via EnvVars.
 	// TODO look to break this up to be more explicit on what is loaded here.
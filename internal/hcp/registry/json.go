@@ -25,9 +25,20 @@ type JSONRegistry struct {
 }
 
 func NewJSONRegistry(config *packer.Core, ui sdkpacker.Ui) (*JSONRegistry, hcl.Diagnostics) {
+	registry, diags := newJSONRegistry(config, ui, withHCPAuthentication)
+	if registry != nil {
+		ui.Say(fmt.Sprintf("Tracking build on HCP Packer with fingerprint %q", registry.bucket.Version.Fingerprint))
+	}
+	return registry, diags
+}
+
+// newJSONRegistry configures the bucket of the config and registers its
+// builds. opts are applied before the configuration of the bucket from the
+// environment.
+func newJSONRegistry(config *packer.Core, ui sdkpacker.Ui, opts ...bucketConfigurationOpts) (*JSONRegistry, hcl.Diagnostics) {
 	bucket, diags := createConfiguredBucket(
 		filepath.Dir(config.Template.Path),
-		withPackerEnvConfiguration,
+		append(opts, withPackerEnvConfiguration)...,
 	)
 
 	if diags.HasErrors() {
@@ -48,8 +59,6 @@ func NewJSONRegistry(config *packer.Core, ui sdkpacker.Ui) (*JSONRegistry, hcl.D
 		bucket.RegisterBuildForComponent(buildName)
 	}
 
-	ui.Say(fmt.Sprintf("Tracking build on HCP Packer with fingerprint %q", bucket.Version.Fingerprint))
-
 	return &JSONRegistry{
 		configuration: config,
 		bucket:        bucket,
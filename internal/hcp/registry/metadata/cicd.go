@@ -6,6 +6,7 @@ package metadata
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 type GithubActions struct{}
@@ -173,12 +174,180 @@ func (g *JenkinsCI) Type() string {
 	return "jenkins"
 }
 
+type AzurePipelines struct{}
+
+func (a *AzurePipelines) Detect() error {
+	_, ok := os.LookupEnv("TF_BUILD")
+	if !ok {
+		return fmt.Errorf("TF_BUILD environment variable not found")
+	}
+	return nil
+}
+
+func (a *AzurePipelines) Details() map[string]interface{} {
+	env := make(map[string]interface{})
+	keys := []string{
+		"BUILD_REPOSITORY_NAME",
+		"BUILD_REPOSITORY_URI",
+		"BUILD_SOURCEVERSION",
+		"BUILD_SOURCEBRANCH",
+		"BUILD_BUILDID",
+		"BUILD_BUILDNUMBER",
+		"BUILD_DEFINITIONNAME",
+		"BUILD_REASON",
+		"BUILD_REQUESTEDFOR",
+		"SYSTEM_COLLECTIONURI",
+		"SYSTEM_TEAMPROJECT",
+		"SYSTEM_JOBDISPLAYNAME",
+		"SYSTEM_PULLREQUEST_PULLREQUESTID",
+		"AGENT_NAME",
+	}
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			env[key] = value
+		}
+	}
+
+	env["BUILD_RESULTS_URL"] = fmt.Sprintf("%s%s/_build/results?buildId=%s", os.Getenv("SYSTEM_COLLECTIONURI"), os.Getenv("SYSTEM_TEAMPROJECT"), os.Getenv("BUILD_BUILDID"))
+	return env
+}
+
+func (a *AzurePipelines) Type() string {
+	return "azure-pipelines"
+}
+
+type Buildkite struct{}
+
+func (b *Buildkite) Detect() error {
+	_, ok := os.LookupEnv("BUILDKITE")
+	if !ok {
+		return fmt.Errorf("BUILDKITE environment variable not found")
+	}
+	return nil
+}
+
+func (b *Buildkite) Details() map[string]interface{} {
+	env := make(map[string]interface{})
+	keys := []string{
+		"BUILDKITE_ORGANIZATION_SLUG",
+		"BUILDKITE_PIPELINE_SLUG",
+		"BUILDKITE_REPO",
+		"BUILDKITE_COMMIT",
+		"BUILDKITE_BRANCH",
+		"BUILDKITE_TAG",
+		"BUILDKITE_BUILD_ID",
+		"BUILDKITE_BUILD_NUMBER",
+		"BUILDKITE_BUILD_URL",
+		"BUILDKITE_JOB_ID",
+		"BUILDKITE_BUILD_CREATOR",
+		"BUILDKITE_SOURCE",
+		"BUILDKITE_PULL_REQUEST",
+		"BUILDKITE_AGENT_NAME",
+	}
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+func (b *Buildkite) Type() string {
+	return "buildkite"
+}
+
+type CircleCI struct{}
+
+func (c *CircleCI) Detect() error {
+	_, ok := os.LookupEnv("CIRCLECI")
+	if !ok {
+		return fmt.Errorf("CIRCLECI environment variable not found")
+	}
+	return nil
+}
+
+func (c *CircleCI) Details() map[string]interface{} {
+	env := make(map[string]interface{})
+	keys := []string{
+		"CIRCLE_PROJECT_USERNAME",
+		"CIRCLE_PROJECT_REPONAME",
+		"CIRCLE_REPOSITORY_URL",
+		"CIRCLE_SHA1",
+		"CIRCLE_BRANCH",
+		"CIRCLE_TAG",
+		"CIRCLE_BUILD_NUM",
+		"CIRCLE_BUILD_URL",
+		"CIRCLE_JOB",
+		"CIRCLE_WORKFLOW_ID",
+		"CIRCLE_USERNAME",
+		"CIRCLE_PULL_REQUEST",
+	}
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+func (c *CircleCI) Type() string {
+	return "circleci"
+}
+
+// customCIPrefix is the prefix of the environment variables that in-house CI
+// systems can set to record their own metadata.
+const customCIPrefix = "PACKER_CI_METADATA_"
+
+// CustomCI passes through the PACKER_CI_METADATA_* environment variables, for
+// CI systems that are not detected otherwise. PACKER_CI_METADATA_TYPE sets the
+// type reported, and defaults to "custom".
+type CustomCI struct{}
+
+func (c *CustomCI) Detect() error {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, customCIPrefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s* environment variable found", customCIPrefix)
+}
+
+func (c *CustomCI) Details() map[string]interface{} {
+	env := make(map[string]interface{})
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, customCIPrefix) || key == customCIPrefix+"TYPE" {
+			continue
+		}
+		env[key] = value
+	}
+
+	return env
+}
+
+func (c *CustomCI) Type() string {
+	if t := os.Getenv(customCIPrefix + "TYPE"); t != "" {
+		return t
+	}
+	return "custom"
+}
+
 func GetCicdMetadata() map[string]interface{} {
 	cicd := []MetadataProvider{
 		&JenkinsCI{},
 		&GithubActions{},
 		&GitlabCI{},
 		&BitbucketPipelines{},
+		&AzurePipelines{},
+		&Buildkite{},
+		&CircleCI{},
+		// CustomCI comes last so that known providers take precedence.
+		&CustomCI{},
 	}
 
 	for _, c := range cicd {
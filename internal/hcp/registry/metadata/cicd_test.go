@@ -0,0 +1,145 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package metadata
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// clearCIEnv unsets the environment variables used to detect CI providers, so
+// that tests do not depend on where they run.
+func clearCIEnv(t *testing.T) {
+	keys := []string{"JENKINS_URL", "GITHUB_ACTIONS", "GITLAB_CI", "BITBUCKET_BUILD_NUMBER", "TF_BUILD", "BUILDKITE", "CIRCLECI"}
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasPrefix(key, customCIPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestGetCicdMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no CI",
+			expected: nil,
+		},
+		{
+			name: "Azure Pipelines",
+			env: map[string]string{
+				"TF_BUILD":              "True",
+				"BUILD_REPOSITORY_NAME": "packer",
+				"BUILD_BUILDID":         "42",
+				"SYSTEM_COLLECTIONURI":  "https://dev.azure.com/org/",
+				"SYSTEM_TEAMPROJECT":    "images",
+			},
+			expected: map[string]interface{}{
+				"type": "azure-pipelines",
+				"details": map[string]interface{}{
+					"BUILD_REPOSITORY_NAME": "packer",
+					"BUILD_BUILDID":         "42",
+					"SYSTEM_COLLECTIONURI":  "https://dev.azure.com/org/",
+					"SYSTEM_TEAMPROJECT":    "images",
+					"BUILD_RESULTS_URL":     "https://dev.azure.com/org/images/_build/results?buildId=42",
+				},
+			},
+		},
+		{
+			name: "Buildkite",
+			env: map[string]string{
+				"BUILDKITE":             "true",
+				"BUILDKITE_COMMIT":      "abc123",
+				"BUILDKITE_BUILD_URL":   "https://buildkite.com/org/images/builds/7",
+				"BUILDKITE_UNSUPPORTED": "ignored",
+			},
+			expected: map[string]interface{}{
+				"type": "buildkite",
+				"details": map[string]interface{}{
+					"BUILDKITE_COMMIT":    "abc123",
+					"BUILDKITE_BUILD_URL": "https://buildkite.com/org/images/builds/7",
+				},
+			},
+		},
+		{
+			name: "CircleCI",
+			env: map[string]string{
+				"CIRCLECI":         "true",
+				"CIRCLE_SHA1":      "abc123",
+				"CIRCLE_BUILD_NUM": "7",
+			},
+			expected: map[string]interface{}{
+				"type": "circleci",
+				"details": map[string]interface{}{
+					"CIRCLE_SHA1":      "abc123",
+					"CIRCLE_BUILD_NUM": "7",
+				},
+			},
+		},
+		{
+			name: "custom",
+			env: map[string]string{
+				"PACKER_CI_METADATA_PIPELINE": "nightly",
+				"PACKER_CI_METADATA_RUN_URL":  "https://ci.example.com/runs/7",
+			},
+			expected: map[string]interface{}{
+				"type": "custom",
+				"details": map[string]interface{}{
+					"PACKER_CI_METADATA_PIPELINE": "nightly",
+					"PACKER_CI_METADATA_RUN_URL":  "https://ci.example.com/runs/7",
+				},
+			},
+		},
+		{
+			name: "custom with type",
+			env: map[string]string{
+				"PACKER_CI_METADATA_TYPE":     "in-house",
+				"PACKER_CI_METADATA_PIPELINE": "nightly",
+			},
+			expected: map[string]interface{}{
+				"type": "in-house",
+				"details": map[string]interface{}{
+					"PACKER_CI_METADATA_PIPELINE": "nightly",
+				},
+			},
+		},
+		{
+			name: "known providers take precedence over custom",
+			env: map[string]string{
+				"CIRCLECI":                    "true",
+				"CIRCLE_SHA1":                 "abc123",
+				"PACKER_CI_METADATA_PIPELINE": "nightly",
+			},
+			expected: map[string]interface{}{
+				"type": "circleci",
+				"details": map[string]interface{}{
+					"CIRCLE_SHA1": "abc123",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCIEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			result := GetCicdMetadata()
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -27,12 +27,22 @@ type Registry interface {
 }
 
 // New instantiates the appropriate registry for the Packer configuration template type.
-// A nullRegistry is returned for non-HCP Packer registry enabled templates.
+// A nullRegistry is returned for non-HCP Packer registry enabled templates, and
+// a SelfHostedRegistry when the location of a self-hosted registry is set.
 func New(cfg packer.Handler, ui sdkpacker.Ui) (Registry, hcl.Diagnostics) {
 	if !IsHCPEnabled(cfg) {
 		return &nullRegistry{}, nil
 	}
 
+	if location := selfHostedLocation(cfg); location != "" {
+		switch config := cfg.(type) {
+		case *hcl2template.PackerConfig:
+			return NewSelfHostedHCLRegistry(config, location, ui)
+		case *packer.Core:
+			return NewSelfHostedJSONRegistry(config, location, ui)
+		}
+	}
+
 	switch config := cfg.(type) {
 	case *hcl2template.PackerConfig:
 		// Maybe rename to what it represents....
@@ -0,0 +1,445 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	hcpPackerModels "github.com/hashicorp/hcp-sdk-go/clients/cloud-packer-service/stable/2023-01-01/models"
+	sdkpacker "github.com/hashicorp/packer-plugin-sdk/packer"
+	packerSDKRegistry "github.com/hashicorp/packer-plugin-sdk/packer/registry/image"
+	"github.com/hashicorp/packer/hcl2template"
+	"github.com/hashicorp/packer/internal/hcp/env"
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+	"github.com/hashicorp/packer/packer"
+	"github.com/oklog/ulid"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SelfHostedRegistry is a handler publishing the builds of a configuration to
+// a self-hosted registry, kept in a local directory or behind an HTTP API,
+// instead of HCP Packer.
+type SelfHostedRegistry struct {
+	store        selfhosted.Store
+	location     string
+	bucket       *Bucket
+	templateType hcpPackerModels.HashicorpCloudPacker20230101TemplateType
+	basedir      string
+	ui           sdkpacker.Ui
+	metadata     *MetadataStore
+	// buildName returns the name of a build in the registry.
+	buildName func(*packer.CoreBuild) string
+	// hcpVars are the HCP variables of an HCL2 configuration, nil for JSON
+	// templates.
+	hcpVars map[string]cty.Value
+}
+
+// selfHostedLocation returns the location of the self-hosted registry the
+// builds of the configuration are published to, or an empty string when they
+// are published to HCP Packer. The HCP_PACKER_REGISTRY_LOCATION environment
+// variable overrides the location set in the configuration.
+func selfHostedLocation(cfg packer.Handler) string {
+	if location := os.Getenv(env.HCPPackerRegistryLocation); location != "" {
+		return location
+	}
+	config, ok := cfg.(*hcl2template.PackerConfig)
+	if !ok {
+		return ""
+	}
+	// Diagnostics are reported when the registry block is read to
+	// configure the bucket.
+	block, _ := config.GetHCPPackerRegistryBlock()
+	if block == nil {
+		return ""
+	}
+	return block.Location
+}
+
+// NewSelfHostedHCLRegistry returns a registry publishing the builds of an HCL2
+// configuration to the self-hosted registry at location.
+func NewSelfHostedHCLRegistry(
+	config *hcl2template.PackerConfig, location string, ui sdkpacker.Ui,
+) (*SelfHostedRegistry, hcl.Diagnostics) {
+	h, diags := newHCLRegistry(config, ui)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	registry, moreDiags := newSelfHostedRegistry(location, h.bucket, ui)
+	diags = diags.Extend(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	registry.templateType = hcpPackerModels.HashicorpCloudPacker20230101TemplateTypeHCL2
+	registry.basedir = config.Basedir
+	registry.metadata = h.metadata
+	registry.buildName = h.HCPBuildName
+	registry.hcpVars = config.HCPVars
+
+	return registry, diags
+}
+
+// NewSelfHostedJSONRegistry returns a registry publishing the builds of a
+// legacy JSON template to the self-hosted registry at location.
+func NewSelfHostedJSONRegistry(config *packer.Core, location string, ui sdkpacker.Ui) (*SelfHostedRegistry, hcl.Diagnostics) {
+	j, diags := newJSONRegistry(config, ui)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	registry, moreDiags := newSelfHostedRegistry(location, j.bucket, ui)
+	diags = diags.Extend(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	registry.templateType = hcpPackerModels.HashicorpCloudPacker20230101TemplateTypeJSON
+	registry.basedir = config.Template.Path
+	registry.metadata = j.metadata
+	registry.buildName = (*packer.CoreBuild).Name
+
+	return registry, diags
+}
+
+func newSelfHostedRegistry(location string, bucket *Bucket, ui sdkpacker.Ui) (*SelfHostedRegistry, hcl.Diagnostics) {
+	store, err := selfhosted.New(location, os.Getenv(env.HCPPackerRegistryToken))
+	if err != nil {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid registry location",
+			Detail:   fmt.Sprintf("Failed to use the registry at %q: %s", location, err),
+		}}
+	}
+
+	ui.Say(fmt.Sprintf("Tracking build on registry %s with fingerprint %q", location, bucket.Version.Fingerprint))
+
+	return &SelfHostedRegistry{
+		store:    store,
+		location: location,
+		bucket:   bucket,
+		ui:       ui,
+	}, nil
+}
+
+// PopulateVersion creates the bucket and the version in the registry, and the
+// builds of the version that do not exist yet.
+func (r *SelfHostedRegistry) PopulateVersion(ctx context.Context) error {
+	if err := r.bucket.Validate(); err != nil {
+		return err
+	}
+
+	bucketName, fingerprint := r.bucket.Name, r.bucket.Version.Fingerprint
+
+	err := r.store.PutBucket(ctx, &selfhosted.Bucket{
+		Name:        bucketName,
+		Description: r.bucket.Description,
+		Labels:      r.bucket.BucketLabels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket %q: %w", bucketName, err)
+	}
+
+	version, err := r.store.GetVersion(ctx, bucketName, fingerprint)
+	if errors.Is(err, selfhosted.ErrNotFound) {
+		version = &selfhosted.Version{
+			ID:           newULID(),
+			Fingerprint:  fingerprint,
+			TemplateType: string(r.templateType),
+		}
+		err = r.store.PutVersion(ctx, bucketName, version)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize version for fingerprint %s: %s", fingerprint, err)
+	}
+
+	if version.TemplateType != "" && version.TemplateType != string(r.templateType) {
+		return fmt.Errorf(
+			"This version was initially created with a %[2]s template. "+
+				"Changing from %[2]s to %[1]s is not supported",
+			r.templateType, version.TemplateType,
+		)
+	}
+
+	if version.IsComplete() {
+		return fmt.Errorf(
+			"The version associated to the fingerprint %v is complete. If you wish to add a new build to "+
+				"this bucket a new version must be created by changing the fingerprint.",
+			fingerprint,
+		)
+	}
+	r.bucket.Version.ID = version.ID
+
+	for _, name := range r.bucket.Version.expectedBuilds {
+		if existing, ok := version.Builds[name]; ok {
+			build := newBuildFromSelfHostedBuild(existing)
+			build.RunUUID = r.bucket.Version.RunUUID
+			if build.IsNotDone() && len(r.bucket.BuildLabels) > 0 {
+				build.MergeLabels(r.bucket.BuildLabels)
+			}
+			log.Printf("[TRACE] a build of component type %s already exists; skipping its creation", name)
+			r.bucket.Version.StoreBuild(name, build)
+			continue
+		}
+
+		build := &Build{
+			ID:            newULID(),
+			ComponentType: name,
+			RunUUID:       r.bucket.Version.RunUUID,
+			Status:        hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDUNSET,
+			Labels:        map[string]string{},
+			Artifacts:     map[string]packerSDKRegistry.Image{},
+		}
+		build.MergeLabels(r.bucket.BuildLabels)
+		if err := r.putBuild(ctx, build); err != nil {
+			return fmt.Errorf("failed to create build %q: %s", name, err)
+		}
+		r.bucket.Version.StoreBuild(name, build)
+	}
+
+	if r.hcpVars != nil {
+		r.hcpVars["iterationID"] = cty.StringVal(version.ID)
+		r.hcpVars["versionFingerprint"] = cty.StringVal(fingerprint)
+	}
+
+	sha, err := getGitSHA(r.basedir)
+	if err != nil {
+		log.Printf("failed to get GIT SHA from environment, won't set as build labels")
+	} else {
+		r.bucket.Version.AddSHAToBuildLabels(sha)
+	}
+
+	return nil
+}
+
+// StartBuild is invoked when one build for the configuration is starting to be processed
+func (r *SelfHostedRegistry) StartBuild(ctx context.Context, build *packer.CoreBuild) error {
+	name := r.buildName(build)
+	if !r.bucket.IsExpectingBuildForComponent(name) {
+		return &ErrBuildAlreadyDone{
+			Message: "build is already done",
+		}
+	}
+
+	err := r.updateBuildStatus(ctx, name, hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDRUNNING)
+	if err != nil {
+		return fmt.Errorf("failed to update build status for %q: %s", name, err)
+	}
+	return nil
+}
+
+// CompleteBuild is invoked when one build for the configuration has finished
+func (r *SelfHostedRegistry) CompleteBuild(
+	ctx context.Context,
+	build *packer.CoreBuild,
+	artifacts []sdkpacker.Artifact,
+	buildErr error,
+) ([]sdkpacker.Artifact, error) {
+	name := r.buildName(build)
+	err := r.bucket.Version.AddMetadataToBuild(ctx, name, build.GetMetadata(), r.metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if buildErr != nil {
+		status := hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDFAILED
+		if ctx.Err() != nil {
+			status = hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDCANCELLED
+		}
+		if err := r.updateBuildStatus(context.Background(), name, status); err != nil {
+			log.Printf("[ERROR] failed to update build %q status to %s: %s", name, status, err)
+		}
+		return artifacts, fmt.Errorf("build failed, not uploading artifacts")
+	}
+
+	res, err := r.completeBuild(ctx, name, artifacts)
+	if err != nil {
+		status := hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDFAILED
+		if err := r.updateBuildStatus(context.Background(), name, status); err != nil {
+			log.Printf("[ERROR] failed to update build %q status to FAILED: %s", name, err)
+		}
+	}
+	return res, err
+}
+
+func (r *SelfHostedRegistry) completeBuild(
+	ctx context.Context, name string, artifacts []sdkpacker.Artifact,
+) ([]sdkpacker.Artifact, error) {
+	if err := r.bucket.addPackerArtifacts(name, artifacts); err != nil {
+		return artifacts, err
+	}
+
+	build, err := r.bucket.Version.Build(name)
+	if err != nil {
+		return artifacts, fmt.Errorf(
+			"failed to get build %q from version being built. This is a Packer bug.",
+			name)
+	}
+	if len(build.Artifacts) == 0 {
+		return artifacts, &NotAHCPArtifactError{
+			fmt.Errorf("No HCP Packer-compatible artifacts were found for the build"),
+		}
+	}
+
+	// Builds must exist in the registry before their SBOMs can be stored.
+	if err := r.putBuild(ctx, build); err != nil {
+		return artifacts, fmt.Errorf("failed to publish artifacts for %q: %s", name, err)
+	}
+
+	bucketName, fingerprint := r.bucket.Name, r.bucket.Version.Fingerprint
+	for i, sbom := range build.CompressedSboms {
+		sbomName := sbom.Name
+		if sbomName == "" {
+			sbomName = fmt.Sprintf("sbom-%d", i+1)
+		}
+		err := r.store.PutSBOM(ctx, bucketName, fingerprint, name, &selfhosted.SBOM{
+			Name:           sbomName,
+			Format:         string(sbom.Format),
+			CompressedData: sbom.CompressedData,
+		})
+		if err != nil {
+			return artifacts, fmt.Errorf("Failed to upload sboms %s", err)
+		}
+	}
+
+	err = r.updateBuildStatus(ctx, name, hcpPackerModels.HashicorpCloudPacker20230101BuildStatusBUILDDONE)
+	if err != nil {
+		return artifacts, fmt.Errorf("failed to update artifacts for %q: %s", name, err)
+	}
+
+	for _, channel := range r.bucket.Channels {
+		r.ui.Say(fmt.Sprintf("==> Assigning version `%s` to channel `%s`", fingerprint, channel))
+		err := r.store.PutChannel(ctx, bucketName, &selfhosted.Channel{
+			Name:               channel,
+			VersionID:          r.bucket.Version.ID,
+			VersionFingerprint: fingerprint,
+		})
+		if err != nil {
+			r.ui.Error(fmt.Sprintf("==> Failed assigning version `%s` to channel `%s`: %v", fingerprint, channel, err))
+			log.Printf("[ERROR] Failed to update channels after completing build %s: %s", name, err)
+			break
+		}
+	}
+
+	return append(artifacts, &registryArtifact{
+		BuildName:  name,
+		BucketName: bucketName,
+		VersionID:  r.bucket.Version.ID,
+		Location:   r.location,
+	}), nil
+}
+
+// VersionStatusSummary prints a status report in the UI if the version is not yet done
+func (r *SelfHostedRegistry) VersionStatusSummary() {
+	r.bucket.Version.statusSummary(r.ui)
+}
+
+// Metadata gets the global metadata object that registers global settings
+func (r *SelfHostedRegistry) Metadata() Metadata {
+	return r.metadata
+}
+
+// FetchEnforcedBlocks does nothing, self-hosted registries do not enforce
+// provisioners.
+func (r *SelfHostedRegistry) FetchEnforcedBlocks(context.Context) error {
+	return nil
+}
+
+// InjectEnforcedProvisioners does nothing, self-hosted registries do not
+// enforce provisioners.
+func (r *SelfHostedRegistry) InjectEnforcedProvisioners([]*packer.CoreBuild) hcl.Diagnostics {
+	return nil
+}
+
+func (r *SelfHostedRegistry) updateBuildStatus(
+	ctx context.Context, name string, status hcpPackerModels.HashicorpCloudPacker20230101BuildStatus,
+) error {
+	build, err := r.bucket.Version.Build(name)
+	if err != nil {
+		return err
+	}
+	build.Status = status
+	return r.putBuild(ctx, build)
+}
+
+func (r *SelfHostedRegistry) putBuild(ctx context.Context, build *Build) error {
+	return r.store.PutBuild(ctx, r.bucket.Name, r.bucket.Version.Fingerprint, newSelfHostedBuild(build))
+}
+
+// newSelfHostedBuild converts a build to the one stored in a self-hosted
+// registry.
+func newSelfHostedBuild(build *Build) *selfhosted.Build {
+	b := &selfhosted.Build{
+		ID:            build.ID,
+		ComponentType: build.ComponentType,
+		Platform:      build.Platform,
+		PackerRunUUID: build.RunUUID,
+		Status:        string(build.Status),
+		Labels:        build.Labels,
+		Metadata:      map[string]interface{}{},
+	}
+
+	keys := make([]string, 0, len(build.Artifacts))
+	for k := range build.Artifacts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	now := time.Now().UTC()
+	for _, k := range keys {
+		artifact := build.Artifacts[k]
+		if artifact.SourceImageID != "" {
+			b.SourceExternalIdentifier = artifact.SourceImageID
+		}
+		b.Artifacts = append(b.Artifacts, selfhosted.Artifact{
+			ExternalIdentifier: artifact.ImageID,
+			Region:             artifact.ProviderRegion,
+			CreatedAt:          now,
+		})
+	}
+
+	for k, v := range map[string]interface{}{
+		"packer": build.Metadata.Packer,
+		"vcs":    build.Metadata.Vcs,
+		"cicd":   build.Metadata.Cicd,
+	} {
+		if m, ok := v.(map[string]interface{}); v == nil || ok && len(m) == 0 {
+			continue
+		}
+		b.Metadata[k] = v
+	}
+	return b
+}
+
+// newBuildFromSelfHostedBuild converts a build stored in a self-hosted
+// registry to a local build that can be tracked.
+func newBuildFromSelfHostedBuild(src *selfhosted.Build) *Build {
+	build := &Build{
+		ID:            src.ID,
+		ComponentType: src.ComponentType,
+		Platform:      src.Platform,
+		RunUUID:       src.PackerRunUUID,
+		Status:        hcpPackerModels.HashicorpCloudPacker20230101BuildStatus(src.Status),
+		Labels:        src.Labels,
+		Artifacts:     map[string]packerSDKRegistry.Image{},
+	}
+	for _, artifact := range src.Artifacts {
+		image := packerSDKRegistry.Image{
+			ImageID:        artifact.ExternalIdentifier,
+			ProviderName:   src.Platform,
+			ProviderRegion: artifact.Region,
+		}
+		build.Artifacts[image.String()] = image
+	}
+	return build
+}
+
+func newULID() string {
+	return ulid.MustNew(ulid.Now(), rand.Reader).String()
+}
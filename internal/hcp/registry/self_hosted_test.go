@@ -0,0 +1,175 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	sdkpacker "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/packer/registry/image"
+	"github.com/hashicorp/packer/hcl2template"
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+	"github.com/hashicorp/packer/internal/hcp/selfhosted/selfhostedtest"
+	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestNew_selfHosted(t *testing.T) {
+	dir := t.TempDir()
+	config := &hcl2template.PackerConfig{
+		Basedir: dir,
+		HCPVars: map[string]cty.Value{},
+		HCPPackerRegistry: &hcl2template.HCPPackerRegistryBlock{
+			Slug:     "ubuntu",
+			Location: dir,
+		},
+		Builds: hcl2template.Builds{
+			&hcl2template.BuildBlock{
+				Sources: []hcl2template.SourceUseBlock{
+					{SourceRef: hcl2template.SourceRef{Type: "docker", Name: "ubuntu"}},
+				},
+			},
+		},
+	}
+
+	reg, diags := New(config, &sdkpacker.BasicUi{Writer: io.Discard, ErrorWriter: io.Discard})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	r, ok := reg.(*SelfHostedRegistry)
+	if !ok {
+		t.Fatalf("expected a self-hosted registry, got %T", reg)
+	}
+	if r.location != dir || r.bucket.Name != "ubuntu" {
+		t.Errorf("unexpected registry configuration: location %q, bucket %q", r.location, r.bucket.Name)
+	}
+
+	// The environment overrides the location set in the configuration.
+	t.Setenv("HCP_PACKER_REGISTRY_LOCATION", "http://localhost:8080")
+	reg, diags = New(config, &sdkpacker.BasicUi{Writer: io.Discard, ErrorWriter: io.Discard})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diags: %s", diags)
+	}
+	if r := reg.(*SelfHostedRegistry); r.location != "http://localhost:8080" {
+		t.Errorf("expected the location of the environment, got %q", r.location)
+	}
+}
+
+func TestSelfHostedRegistry(t *testing.T) {
+	server := httptest.NewServer(selfhostedtest.NewHandler(selfhosted.NewDirStore(t.TempDir()), ""))
+	defer server.Close()
+
+	for name, location := range map[string]string{
+		"directory": t.TempDir(),
+		"http":      server.URL,
+	} {
+		t.Run(name, func(t *testing.T) {
+			testSelfHostedRegistry(t, location)
+		})
+	}
+}
+
+func testSelfHostedRegistry(t *testing.T, location string) {
+	ctx := context.Background()
+	ui := &sdkpacker.BasicUi{Reader: os.Stdin, Writer: io.Discard, ErrorWriter: io.Discard}
+
+	newRegistry := func() *SelfHostedRegistry {
+		bucket := NewBucketWithVersion()
+		bucket.Name = "ubuntu"
+		bucket.BuildLabels = map[string]string{"team": "infra"}
+		bucket.Channels = []string{"latest"}
+		bucket.Version.Fingerprint = "fp1"
+		bucket.RegisterBuildForComponent("docker.ubuntu")
+		bucket.RegisterBuildForComponent("docker.alpine")
+
+		r, diags := newSelfHostedRegistry(location, bucket, ui)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diags: %s", diags)
+		}
+		r.templateType = "HCL2"
+		r.basedir = t.TempDir()
+		r.metadata = &MetadataStore{}
+		r.buildName = func(b *packer.CoreBuild) string { return b.Type }
+		return r
+	}
+
+	r := newRegistry()
+	if err := r.PopulateVersion(ctx); err != nil {
+		t.Fatalf("PopulateVersion: %s", err)
+	}
+
+	ubuntu := &packer.CoreBuild{Type: "docker.ubuntu"}
+	if err := r.StartBuild(ctx, ubuntu); err != nil {
+		t.Fatalf("StartBuild: %s", err)
+	}
+	artifact := &sdkpacker.MockArtifact{
+		BuilderIdValue: "builder.test",
+		StateValues: map[string]interface{}{
+			image.ArtifactStateURI: &image.Image{
+				ImageID:        "sha256:1234",
+				ProviderName:   "docker",
+				ProviderRegion: "local",
+			},
+		},
+	}
+	artifacts, err := r.CompleteBuild(ctx, ubuntu, []sdkpacker.Artifact{artifact}, nil)
+	if err != nil {
+		t.Fatalf("CompleteBuild: %s", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("expected the registry artifact to be added, got %v", artifacts)
+	}
+
+	alpine := &packer.CoreBuild{Type: "docker.alpine"}
+	if err := r.StartBuild(ctx, alpine); err != nil {
+		t.Fatalf("StartBuild: %s", err)
+	}
+	if _, err := r.CompleteBuild(ctx, alpine, nil, errors.New("boom")); err == nil {
+		t.Fatal("CompleteBuild should fail for failed builds")
+	}
+
+	store, _ := selfhosted.New(location, "")
+	version, err := store.GetVersion(ctx, "ubuntu", "fp1")
+	if err != nil {
+		t.Fatalf("GetVersion: %s", err)
+	}
+	if version.TemplateType != "HCL2" || version.IsComplete() {
+		t.Errorf("unexpected version %#v", version)
+	}
+	build := version.Builds["docker.ubuntu"]
+	if build.Status != selfhosted.BuildStatusDone || build.Platform != "docker" || build.Labels["team"] != "infra" ||
+		len(build.Artifacts) != 1 || build.Artifacts[0].ExternalIdentifier != "sha256:1234" {
+		t.Errorf("unexpected build %#v", build)
+	}
+	if status := version.Builds["docker.alpine"].Status; status != selfhosted.BuildStatusFailed {
+		t.Errorf("expected the failed build to be %s, got %s", selfhosted.BuildStatusFailed, status)
+	}
+	channel, err := store.GetChannel(ctx, "ubuntu", "latest")
+	if err != nil {
+		t.Fatalf("GetChannel: %s", err)
+	}
+	if channel.VersionFingerprint != "fp1" || channel.VersionID != version.ID {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+
+	// A second run with the same fingerprint only runs the remaining build.
+	r = newRegistry()
+	if err := r.PopulateVersion(ctx); err != nil {
+		t.Fatalf("PopulateVersion: %s", err)
+	}
+	if r.bucket.Version.ID != version.ID {
+		t.Errorf("expected the existing version %q to be reused, got %q", version.ID, r.bucket.Version.ID)
+	}
+	if r.bucket.IsExpectingBuildForComponent("docker.ubuntu") {
+		t.Error("the done build should not be expected again")
+	}
+	if !r.bucket.IsExpectingBuildForComponent("docker.alpine") {
+		t.Error("the failed build should be expected again")
+	}
+}
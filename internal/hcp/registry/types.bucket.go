@@ -762,35 +762,8 @@ func (bucket *Bucket) doCompleteBuild(
 	ui packerSDK.Ui,
 	buildErr error,
 ) ([]packerSDK.Artifact, error) {
-	for _, art := range packerSDKArtifacts {
-		var sdkImages []packerSDKRegistry.Image
-		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-			Result:           &sdkImages,
-			WeaklyTypedInput: true,
-			ErrorUnused:      false,
-		})
-		if err != nil {
-			return packerSDKArtifacts, fmt.Errorf(
-				"failed to create decoder for HCP Packer artifact: %w",
-				err)
-		}
-
-		state := art.State(packerSDKRegistry.ArtifactStateURI)
-		if state == nil {
-			log.Printf("[WARN] - artifact %q returned a nil value for the HCP state, ignoring", art.BuilderId())
-			continue
-		}
-
-		err = decoder.Decode(state)
-		if err != nil {
-			log.Printf("[WARN] - artifact %q failed to be decoded to an HCP artifact, this is probably because it is not compatible: %s", art.BuilderId(), err)
-			continue
-		}
-
-		err = bucket.UpdateArtifactForBuild(buildName, sdkImages...)
-		if err != nil {
-			return packerSDKArtifacts, fmt.Errorf("failed to add artifact for %q: %s", buildName, err)
-		}
+	if err := bucket.addPackerArtifacts(buildName, packerSDKArtifacts); err != nil {
+		return packerSDKArtifacts, err
 	}
 
 	build, err := bucket.Version.Build(buildName)
@@ -832,3 +805,40 @@ func (bucket *Bucket) doCompleteBuild(
 		VersionID:  bucket.Version.ID,
 	}), nil
 }
+
+// addPackerArtifacts adds the HCP Packer-compatible artifacts among
+// packerSDKArtifacts to the build referred to by buildName. Incompatible
+// artifacts are ignored.
+func (bucket *Bucket) addPackerArtifacts(buildName string, packerSDKArtifacts []packerSDK.Artifact) error {
+	for _, art := range packerSDKArtifacts {
+		var sdkImages []packerSDKRegistry.Image
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			Result:           &sdkImages,
+			WeaklyTypedInput: true,
+			ErrorUnused:      false,
+		})
+		if err != nil {
+			return fmt.Errorf(
+				"failed to create decoder for HCP Packer artifact: %w",
+				err)
+		}
+
+		state := art.State(packerSDKRegistry.ArtifactStateURI)
+		if state == nil {
+			log.Printf("[WARN] - artifact %q returned a nil value for the HCP state, ignoring", art.BuilderId())
+			continue
+		}
+
+		err = decoder.Decode(state)
+		if err != nil {
+			log.Printf("[WARN] - artifact %q failed to be decoded to an HCP artifact, this is probably because it is not compatible: %s", art.BuilderId(), err)
+			continue
+		}
+
+		err = bucket.UpdateArtifactForBuild(buildName, sdkImages...)
+		if err != nil {
+			return fmt.Errorf("failed to add artifact for %q: %s", buildName, err)
+		}
+	}
+	return nil
+}
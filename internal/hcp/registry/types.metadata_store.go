@@ -33,6 +33,16 @@ func (ms *MetadataStore) Gather(args map[string]interface{}) {
 	ms.PackerBuildCommandOptions = args
 }
 
+// BuildMetadata returns the gathered environment information, as exposed to
+// templates and post-processors outside of HCP Packer.
+func (ms *MetadataStore) BuildMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"os":   ms.OperatingSystem,
+		"vcs":  ms.Vcs,
+		"cicd": ms.Cicd,
+	}
+}
+
 // NilMetadata is a dummy implementation of a Metadata that does nothing.
 //
 // It is the implementation used typically when HCP is disabled, so nothing is
@@ -0,0 +1,258 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package selfhosted
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const lockRetryDelay = 100 * time.Millisecond
+
+// DirStore is a Store keeping the registry in a local directory, which can be
+// shared between machines through a network file system. Each bucket is a
+// directory with the following layout:
+//
+//	<bucket>/bucket.json
+//	<bucket>/channels/<channel>.json
+//	<bucket>/versions/<fingerprint>/version.json
+//	<bucket>/versions/<fingerprint>/sboms/<build>/<sbom>.json
+//
+// Updates of a bucket are serialised with a lock file, so several Packer
+// processes can use the same directory.
+type DirStore struct {
+	Dir string
+}
+
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{Dir: dir}
+}
+
+func (s *DirStore) PutBucket(ctx context.Context, bucket *Bucket) error {
+	if err := checkNames(bucket.Name); err != nil {
+		return err
+	}
+	return s.withLock(ctx, bucket.Name, func() error {
+		path := filepath.Join(s.Dir, bucket.Name, "bucket.json")
+		var existing Bucket
+		switch err := readJSON(path, &existing); {
+		case err == nil:
+			bucket.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, ErrNotFound):
+			return err
+		}
+		now := time.Now().UTC()
+		if bucket.CreatedAt.IsZero() {
+			bucket.CreatedAt = now
+		}
+		bucket.UpdatedAt = now
+		return writeJSON(path, bucket)
+	})
+}
+
+func (s *DirStore) GetVersion(_ context.Context, bucket, fingerprint string) (*Version, error) {
+	if err := checkNames(bucket, fingerprint); err != nil {
+		return nil, err
+	}
+	version := &Version{}
+	if err := readJSON(s.versionPath(bucket, fingerprint), version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (s *DirStore) PutVersion(ctx context.Context, bucket string, version *Version) error {
+	if err := checkNames(bucket, version.Fingerprint); err != nil {
+		return err
+	}
+	return s.withLock(ctx, bucket, func() error {
+		path := s.versionPath(bucket, version.Fingerprint)
+		stored := *version
+		var existing Version
+		switch err := readJSON(path, &existing); {
+		case err == nil:
+			stored.CreatedAt = existing.CreatedAt
+			stored.Builds = existing.Builds
+		case errors.Is(err, ErrNotFound):
+			stored.Builds = nil
+		default:
+			return err
+		}
+		now := time.Now().UTC()
+		if stored.CreatedAt.IsZero() {
+			stored.CreatedAt = now
+		}
+		stored.UpdatedAt = now
+		return writeJSON(path, &stored)
+	})
+}
+
+func (s *DirStore) PutBuild(ctx context.Context, bucket, fingerprint string, build *Build) error {
+	if err := checkNames(bucket, fingerprint, build.ComponentType); err != nil {
+		return err
+	}
+	return s.updateVersion(ctx, bucket, fingerprint, func(version *Version) error {
+		now := time.Now().UTC()
+		if existing, ok := version.Builds[build.ComponentType]; ok {
+			build.CreatedAt = existing.CreatedAt
+			if build.SBOMs == nil {
+				build.SBOMs = existing.SBOMs
+			}
+		}
+		if build.CreatedAt.IsZero() {
+			build.CreatedAt = now
+		}
+		build.UpdatedAt = now
+		if version.Builds == nil {
+			version.Builds = map[string]*Build{}
+		}
+		version.Builds[build.ComponentType] = build
+		return nil
+	})
+}
+
+func (s *DirStore) PutSBOM(ctx context.Context, bucket, fingerprint, build string, sbom *SBOM) error {
+	if err := checkNames(bucket, fingerprint, build, sbom.Name); err != nil {
+		return err
+	}
+	return s.updateVersion(ctx, bucket, fingerprint, func(version *Version) error {
+		b, ok := version.Builds[build]
+		if !ok {
+			return fmt.Errorf("build %q: %w", build, ErrNotFound)
+		}
+		path := filepath.Join(s.Dir, bucket, "versions", fingerprint, "sboms", build, sbom.Name+".json")
+		if err := writeJSON(path, sbom); err != nil {
+			return err
+		}
+		for _, name := range b.SBOMs {
+			if name == sbom.Name {
+				return nil
+			}
+		}
+		b.SBOMs = append(b.SBOMs, sbom.Name)
+		return nil
+	})
+}
+
+func (s *DirStore) GetChannel(_ context.Context, bucket, channel string) (*Channel, error) {
+	if err := checkNames(bucket, channel); err != nil {
+		return nil, err
+	}
+	c := &Channel{}
+	if err := readJSON(s.channelPath(bucket, channel), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *DirStore) PutChannel(ctx context.Context, bucket string, channel *Channel) error {
+	if err := checkNames(bucket, channel.Name); err != nil {
+		return err
+	}
+	return s.withLock(ctx, bucket, func() error {
+		if _, err := os.Stat(s.versionPath(bucket, channel.VersionFingerprint)); err != nil {
+			return fmt.Errorf("version %q: %w", channel.VersionFingerprint, ErrNotFound)
+		}
+		channel.UpdatedAt = time.Now().UTC()
+		return writeJSON(s.channelPath(bucket, channel.Name), channel)
+	})
+}
+
+func (s *DirStore) versionPath(bucket, fingerprint string) string {
+	return filepath.Join(s.Dir, bucket, "versions", fingerprint, "version.json")
+}
+
+func (s *DirStore) channelPath(bucket, channel string) string {
+	return filepath.Join(s.Dir, bucket, "channels", channel+".json")
+}
+
+// updateVersion reads a version, calls update on it and writes it back, while
+// holding the lock of the bucket.
+func (s *DirStore) updateVersion(ctx context.Context, bucket, fingerprint string, update func(*Version) error) error {
+	return s.withLock(ctx, bucket, func() error {
+		path := s.versionPath(bucket, fingerprint)
+		var version Version
+		if err := readJSON(path, &version); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return fmt.Errorf("version %q: %w", fingerprint, ErrNotFound)
+			}
+			return err
+		}
+		if err := update(&version); err != nil {
+			return err
+		}
+		version.UpdatedAt = time.Now().UTC()
+		return writeJSON(path, &version)
+	})
+}
+
+// withLock calls f while holding the lock of the bucket. The lock file is
+// left in place: removing it while another process waits on it would let two
+// processes hold the lock at the same time.
+func (s *DirStore) withLock(ctx context.Context, bucket string, f func() error) error {
+	dir := filepath.Join(s.Dir, bucket)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	lock := flock.New(filepath.Join(dir, ".lock"))
+	locked, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if !locked {
+		if err == nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("unable to lock bucket %q: %s", bucket, err)
+	}
+	defer lock.Unlock()
+	return f()
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %s", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v to path through a temporary file, so that readers never
+// see a partially written file.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,6 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package selfhosted
+
+var TestStore = testStore
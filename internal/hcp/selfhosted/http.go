@@ -0,0 +1,142 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package selfhosted
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HTTPStore is a Store keeping the registry behind an HTTP/JSON API. Objects
+// are read with GET requests and written with PUT requests, using the JSON
+// encoding of the types of this package, on the following paths relative to
+// the URL of the store:
+//
+//	buckets/{bucket}
+//	buckets/{bucket}/channels/{channel}
+//	buckets/{bucket}/versions/{fingerprint}
+//	buckets/{bucket}/versions/{fingerprint}/builds/{build}
+//	buckets/{bucket}/versions/{fingerprint}/builds/{build}/sboms/{sbom}
+//
+// A 404 status code is returned for objects that do not exist, other errors
+// are returned with a 4xx or 5xx status code and an {"error": "message"} body.
+//
+// selfhostedtest.NewHandler serves this API from any Store, for tests.
+type HTTPStore struct {
+	URL string
+	// Token, when set, is sent as a bearer token with every request.
+	Token  string
+	Client *http.Client
+}
+
+func NewHTTPStore(url, token string) *HTTPStore {
+	return &HTTPStore{
+		URL:    strings.TrimSuffix(url, "/"),
+		Token:  token,
+		Client: http.DefaultClient,
+	}
+}
+
+func (s *HTTPStore) PutBucket(ctx context.Context, bucket *Bucket) error {
+	return s.do(ctx, http.MethodPut, bucket, nil, "buckets", bucket.Name)
+}
+
+func (s *HTTPStore) GetVersion(ctx context.Context, bucket, fingerprint string) (*Version, error) {
+	version := &Version{}
+	if err := s.do(ctx, http.MethodGet, nil, version, "buckets", bucket, "versions", fingerprint); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (s *HTTPStore) PutVersion(ctx context.Context, bucket string, version *Version) error {
+	return s.do(ctx, http.MethodPut, version, nil, "buckets", bucket, "versions", version.Fingerprint)
+}
+
+func (s *HTTPStore) PutBuild(ctx context.Context, bucket, fingerprint string, build *Build) error {
+	return s.do(ctx, http.MethodPut, build, nil, "buckets", bucket, "versions", fingerprint, "builds", build.ComponentType)
+}
+
+func (s *HTTPStore) PutSBOM(ctx context.Context, bucket, fingerprint, build string, sbom *SBOM) error {
+	return s.do(ctx, http.MethodPut, sbom, nil, "buckets", bucket, "versions", fingerprint, "builds", build, "sboms", sbom.Name)
+}
+
+func (s *HTTPStore) GetChannel(ctx context.Context, bucket, channel string) (*Channel, error) {
+	c := &Channel{}
+	if err := s.do(ctx, http.MethodGet, nil, c, "buckets", bucket, "channels", channel); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *HTTPStore) PutChannel(ctx context.Context, bucket string, channel *Channel) error {
+	return s.do(ctx, http.MethodPut, channel, nil, "buckets", bucket, "channels", channel.Name)
+}
+
+// do sends a request with the JSON encoding of in as body to the path made of
+// segments, and decodes the response body into out when it is set.
+func (s *HTTPStore) do(ctx context.Context, method string, in, out interface{}, segments ...string) error {
+	if err := checkNames(segments...); err != nil {
+		return err
+	}
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	u := s.URL + "/" + strings.Join(escaped, "/")
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s %s: %w", method, u, ErrNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return fmt.Errorf("%s %s: %s", method, u, e.Error)
+	case out != nil:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response of %s %s: %s", method, u, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package selfhosted_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+	"github.com/hashicorp/packer/internal/hcp/selfhosted/selfhostedtest"
+)
+
+func TestHTTPStore(t *testing.T) {
+	server := httptest.NewServer(selfhostedtest.NewHandler(selfhosted.NewDirStore(t.TempDir()), ""))
+	defer server.Close()
+
+	selfhosted.TestStore(t, selfhosted.NewHTTPStore(server.URL+"/", ""))
+}
+
+func TestHTTPStore_token(t *testing.T) {
+	server := httptest.NewServer(selfhostedtest.NewHandler(selfhosted.NewDirStore(t.TempDir()), "secret"))
+	defer server.Close()
+
+	ctx := context.Background()
+	err := selfhosted.NewHTTPStore(server.URL, "wrong").PutBucket(ctx, &selfhosted.Bucket{Name: "bucket"})
+	if err == nil || !strings.Contains(err.Error(), "invalid or missing token") {
+		t.Fatalf("expected an authentication error, got %v", err)
+	}
+	selfhosted.TestStore(t, selfhosted.NewHTTPStore(server.URL, "secret"))
+}
@@ -0,0 +1,128 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package selfhostedtest provides a server of the self-hosted registry API,
+// to test HTTP stores against.
+package selfhostedtest
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/packer/internal/hcp/selfhosted"
+)
+
+// NewHandler returns an http.Handler serving the API used by
+// selfhosted.HTTPStore from store, usually a selfhosted.DirStore, as a
+// stand-in server in tests.
+//
+// When token is set, requests must authenticate with it as bearer token.
+func NewHandler(store selfhosted.Store, token string) http.Handler {
+	h := &handler{store: store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /buckets/{bucket}", h.putBucket)
+	mux.HandleFunc("GET /buckets/{bucket}/versions/{fingerprint}", h.getVersion)
+	mux.HandleFunc("PUT /buckets/{bucket}/versions/{fingerprint}", h.putVersion)
+	mux.HandleFunc("PUT /buckets/{bucket}/versions/{fingerprint}/builds/{build}", h.putBuild)
+	mux.HandleFunc("PUT /buckets/{bucket}/versions/{fingerprint}/builds/{build}/sboms/{sbom}", h.putSBOM)
+	mux.HandleFunc("GET /buckets/{bucket}/channels/{channel}", h.getChannel)
+	mux.HandleFunc("PUT /buckets/{bucket}/channels/{channel}", h.putChannel)
+	if token == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+type handler struct {
+	store selfhosted.Store
+}
+
+func (h *handler) putBucket(w http.ResponseWriter, r *http.Request) {
+	var bucket selfhosted.Bucket
+	if !decodeBody(w, r, &bucket) {
+		return
+	}
+	bucket.Name = r.PathValue("bucket")
+	writeResult(w, &bucket, h.store.PutBucket(r.Context(), &bucket))
+}
+
+func (h *handler) getVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := h.store.GetVersion(r.Context(), r.PathValue("bucket"), r.PathValue("fingerprint"))
+	writeResult(w, version, err)
+}
+
+func (h *handler) putVersion(w http.ResponseWriter, r *http.Request) {
+	var version selfhosted.Version
+	if !decodeBody(w, r, &version) {
+		return
+	}
+	version.Fingerprint = r.PathValue("fingerprint")
+	writeResult(w, &version, h.store.PutVersion(r.Context(), r.PathValue("bucket"), &version))
+}
+
+func (h *handler) putBuild(w http.ResponseWriter, r *http.Request) {
+	var build selfhosted.Build
+	if !decodeBody(w, r, &build) {
+		return
+	}
+	build.ComponentType = r.PathValue("build")
+	writeResult(w, &build, h.store.PutBuild(r.Context(), r.PathValue("bucket"), r.PathValue("fingerprint"), &build))
+}
+
+func (h *handler) putSBOM(w http.ResponseWriter, r *http.Request) {
+	var sbom selfhosted.SBOM
+	if !decodeBody(w, r, &sbom) {
+		return
+	}
+	sbom.Name = r.PathValue("sbom")
+	err := h.store.PutSBOM(r.Context(), r.PathValue("bucket"), r.PathValue("fingerprint"), r.PathValue("build"), &sbom)
+	writeResult(w, &sbom, err)
+}
+
+func (h *handler) getChannel(w http.ResponseWriter, r *http.Request) {
+	channel, err := h.store.GetChannel(r.Context(), r.PathValue("bucket"), r.PathValue("channel"))
+	writeResult(w, channel, err)
+}
+
+func (h *handler) putChannel(w http.ResponseWriter, r *http.Request) {
+	var channel selfhosted.Channel
+	if !decodeBody(w, r, &channel) {
+		return
+	}
+	channel.Name = r.PathValue("channel")
+	writeResult(w, &channel, h.store.PutChannel(r.Context(), r.PathValue("bucket"), &channel))
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func writeResult(w http.ResponseWriter, v interface{}, err error) {
+	switch {
+	case errors.Is(err, selfhosted.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
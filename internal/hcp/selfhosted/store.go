@@ -0,0 +1,148 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package selfhosted provides storage for artifact registries that are not
+// hosted on HCP.
+//
+// A self-hosted registry records the same data as HCP Packer: buckets,
+// versions identified by their fingerprint, the builds of a version with their
+// artifacts, labels and SBOMs, and channels pointing to a version. The state is
+// kept either in a local directory, or behind a generic HTTP/JSON API.
+package selfhosted
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when the requested object does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Build statuses, matching the ones of HCP Packer.
+const (
+	BuildStatusUnset     = "BUILD_UNSET"
+	BuildStatusRunning   = "BUILD_RUNNING"
+	BuildStatusDone      = "BUILD_DONE"
+	BuildStatusFailed    = "BUILD_FAILED"
+	BuildStatusCancelled = "BUILD_CANCELLED"
+)
+
+// Store persists the state of a self-hosted registry.
+type Store interface {
+	// PutBucket creates or updates a bucket.
+	PutBucket(ctx context.Context, bucket *Bucket) error
+	// GetVersion returns the version of the bucket with that fingerprint,
+	// with all its builds.
+	GetVersion(ctx context.Context, bucket, fingerprint string) (*Version, error)
+	// PutVersion creates or updates a version. The builds of the version are
+	// not updated, use PutBuild for that.
+	PutVersion(ctx context.Context, bucket string, version *Version) error
+	// PutBuild creates or updates a build of an existing version.
+	PutBuild(ctx context.Context, bucket, fingerprint string, build *Build) error
+	// PutSBOM stores an SBOM of a build of an existing version.
+	PutSBOM(ctx context.Context, bucket, fingerprint, build string, sbom *SBOM) error
+	// GetChannel returns a channel of the bucket.
+	GetChannel(ctx context.Context, bucket, channel string) (*Channel, error)
+	// PutChannel creates or updates a channel of the bucket.
+	PutChannel(ctx context.Context, bucket string, channel *Channel) error
+}
+
+type Bucket struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type Version struct {
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint"`
+	// TemplateType is the type of template used to create the version, HCL2
+	// or JSON.
+	TemplateType string            `json:"template_type"`
+	Builds       map[string]*Build `json:"builds,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// IsComplete tells whether the version has builds, and all of them are done.
+func (v *Version) IsComplete() bool {
+	if len(v.Builds) == 0 {
+		return false
+	}
+	for _, b := range v.Builds {
+		if b.Status != BuildStatusDone {
+			return false
+		}
+	}
+	return true
+}
+
+type Build struct {
+	ID string `json:"id"`
+	// ComponentType is the name of the build in the version, for example
+	// `docker.ubuntu`.
+	ComponentType string `json:"component_type"`
+	Platform      string `json:"platform,omitempty"`
+	PackerRunUUID string `json:"packer_run_uuid,omitempty"`
+	Status        string `json:"status"`
+	// SourceExternalIdentifier is the identifier of the artifact the build
+	// started from, if any.
+	SourceExternalIdentifier string                 `json:"source_external_identifier,omitempty"`
+	Labels                   map[string]string      `json:"labels,omitempty"`
+	Artifacts                []Artifact             `json:"artifacts,omitempty"`
+	Metadata                 map[string]interface{} `json:"metadata,omitempty"`
+	// SBOMs are the names of the SBOMs stored for the build.
+	SBOMs     []string  `json:"sboms,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Artifact struct {
+	ExternalIdentifier string    `json:"external_identifier"`
+	Region             string    `json:"region"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type Channel struct {
+	Name               string    `json:"name"`
+	VersionID          string    `json:"version_id"`
+	VersionFingerprint string    `json:"version_fingerprint"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type SBOM struct {
+	Name string `json:"name"`
+	// Format is the format of the SBOM, CYCLONEDX or SPDX.
+	Format string `json:"format"`
+	// CompressedData is the compressed content of the SBOM.
+	CompressedData []byte `json:"compressed_data"`
+}
+
+// New returns the Store for a location: URLs with an http or https scheme are
+// served by an HTTPStore, other locations are local directories. The token is
+// only used by HTTP stores, to authenticate requests.
+func New(location, token string) (Store, error) {
+	switch {
+	case location == "":
+		return nil, errors.New("the location of the registry is empty")
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return NewHTTPStore(location, token), nil
+	}
+	return NewDirStore(location), nil
+}
+
+// checkNames makes sure names can be used as path segments, so that they
+// cannot reference files or routes outside of the registry.
+func checkNames(names ...string) error {
+	for _, name := range names {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid name %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package selfhosted
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDirStore(t *testing.T) {
+	testStore(t, NewDirStore(t.TempDir()))
+}
+
+func TestNew(t *testing.T) {
+	for location, want := range map[string]Store{
+		"https://registry.example.com/api": NewHTTPStore("https://registry.example.com/api", "token"),
+		"http://localhost:8080":            NewHTTPStore("http://localhost:8080", "token"),
+		"./registry":                       NewDirStore("./registry"),
+	} {
+		got, err := New(location, "token")
+		if err != nil {
+			t.Fatalf("New(%q): %s", location, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("New(%q) = %#v, want %#v", location, got, want)
+		}
+	}
+	if _, err := New("", ""); err == nil {
+		t.Error("New should fail without a location")
+	}
+}
+
+// testStore runs the same scenario on every Store implementation.
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if err := store.PutBucket(ctx, &Bucket{Name: "ubuntu", Labels: map[string]string{"os": "ubuntu"}}); err != nil {
+		t.Fatalf("PutBucket: %s", err)
+	}
+
+	if _, err := store.GetVersion(ctx, "ubuntu", "fp1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetVersion of a missing version should return ErrNotFound, got %v", err)
+	}
+	if err := store.PutBuild(ctx, "ubuntu", "fp1", &Build{ComponentType: "docker.ubuntu"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("PutBuild of a missing version should return ErrNotFound, got %v", err)
+	}
+
+	if err := store.PutVersion(ctx, "ubuntu", &Version{ID: "v1", Fingerprint: "fp1", TemplateType: "HCL2"}); err != nil {
+		t.Fatalf("PutVersion: %s", err)
+	}
+	build := &Build{
+		ID:            "b1",
+		ComponentType: "docker.ubuntu",
+		Platform:      "docker",
+		Status:        BuildStatusDone,
+		Labels:        map[string]string{"git_sha": "abc"},
+		Artifacts:     []Artifact{{ExternalIdentifier: "sha256:1234", Region: "local"}},
+	}
+	if err := store.PutBuild(ctx, "ubuntu", "fp1", build); err != nil {
+		t.Fatalf("PutBuild: %s", err)
+	}
+	sbom := &SBOM{Name: "sbom", Format: "SPDX", CompressedData: []byte{0x1f, 0x8b}}
+	if err := store.PutSBOM(ctx, "ubuntu", "fp1", "docker.ubuntu", sbom); err != nil {
+		t.Fatalf("PutSBOM: %s", err)
+	}
+	// Updating the version must not remove its builds.
+	if err := store.PutVersion(ctx, "ubuntu", &Version{ID: "v1", Fingerprint: "fp1", TemplateType: "HCL2"}); err != nil {
+		t.Fatalf("PutVersion: %s", err)
+	}
+
+	version, err := store.GetVersion(ctx, "ubuntu", "fp1")
+	if err != nil {
+		t.Fatalf("GetVersion: %s", err)
+	}
+	got := version.Builds["docker.ubuntu"]
+	if got == nil {
+		t.Fatalf("build not found in version: %#v", version)
+	}
+	if got.ID != "b1" || got.Status != BuildStatusDone || got.Labels["git_sha"] != "abc" ||
+		len(got.Artifacts) != 1 || got.Artifacts[0].ExternalIdentifier != "sha256:1234" {
+		t.Errorf("unexpected build %#v", got)
+	}
+	if !reflect.DeepEqual(got.SBOMs, []string{"sbom"}) {
+		t.Errorf("expected the SBOM to be listed in the build, got %v", got.SBOMs)
+	}
+	if !version.IsComplete() {
+		t.Error("version should be complete")
+	}
+
+	if err := store.PutChannel(ctx, "ubuntu", &Channel{Name: "latest", VersionFingerprint: "missing"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("PutChannel of a missing version should return ErrNotFound, got %v", err)
+	}
+	if err := store.PutChannel(ctx, "ubuntu", &Channel{Name: "latest", VersionID: "v1", VersionFingerprint: "fp1"}); err != nil {
+		t.Fatalf("PutChannel: %s", err)
+	}
+	channel, err := store.GetChannel(ctx, "ubuntu", "latest")
+	if err != nil {
+		t.Fatalf("GetChannel: %s", err)
+	}
+	if channel.VersionFingerprint != "fp1" || channel.VersionID != "v1" {
+		t.Errorf("unexpected channel %#v", channel)
+	}
+	if _, err := store.GetChannel(ctx, "ubuntu", "stable"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetChannel of a missing channel should return ErrNotFound, got %v", err)
+	}
+
+	if _, err := store.GetVersion(ctx, "ubuntu", ".."); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetVersion should refuse invalid names, got %v", err)
+	}
+}
@@ -385,6 +385,8 @@ func loadConfig() (*config, error) {
 		return nil, fmt.Errorf("%s: %s", configFilePath, err)
 	}
 
+	config.Plugins.Mirror = config.PluginMirror
+
 	return &config, nil
 }
 
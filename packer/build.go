@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/packer-plugin-sdk/packerbuilderdata"
 	"github.com/hashicorp/packer/version"
 	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/sync/errgroup"
 )
 
 // A CoreBuild struct represents a single build job, the result of which should
@@ -43,6 +44,12 @@ type CoreBuild struct {
 	TemplatePath       string
 	Variables          map[string]string
 	SensitiveVars      []string
+	// EnvMetadata is the metadata gathered from the environment of the build,
+	// passed to post-processors in the "PackerBuildMetadata" generated data.
+	EnvMetadata map[string]interface{}
+	// Conditions are the preconditions and postconditions of the build; they
+	// can only be set from HCL2 templates.
+	Conditions BuildConditions
 
 	// Indicates whether the build is already initialized before calling Prepare(..)
 	Prepared bool
@@ -55,6 +62,19 @@ type CoreBuild struct {
 	generatedVars []string
 
 	SBOMs []SBOM
+
+	report                   *BuildReport
+	postProcessorParallelism int64
+}
+
+// BuildConditions checks facts about a build while it runs, and fails it when
+// they don't hold.
+type BuildConditions interface {
+	// CheckPreconditions is called before the builder starts.
+	CheckPreconditions() error
+	// CheckPostconditions is called with the artifact of the builder, before
+	// running the post-processors.
+	CheckPostconditions(packersdk.Artifact) error
 }
 
 type SBOM struct {
@@ -96,6 +116,69 @@ func (b *CoreBuild) getPluginsMetadata() map[string]PluginDetails {
 	return resp
 }
 
+// pluginVersions returns the version of every plugin used by the build,
+// indexed by plugin name.
+func (b *CoreBuild) pluginVersions() map[string]string {
+	versions := map[string]string{}
+	for name, details := range b.getPluginsMetadata() {
+		versions[name] = details.Description.Version
+	}
+	return versions
+}
+
+// buildMetadataArtifact is the artifact handed to post-processors. On top of
+// the state of the wrapped artifact, it exposes the versions of the plugins
+// used by the build under the "packer_plugin_versions" state key, and the
+// metadata of the environment under the "PackerBuildMetadata" key of the
+// generated data.
+type buildMetadataArtifact struct {
+	packersdk.Artifact
+	pluginVersions map[string]string
+	envMetadata    map[string]interface{}
+}
+
+func (a *buildMetadataArtifact) State(name string) interface{} {
+	switch name {
+	case "packer_plugin_versions":
+		return a.pluginVersions
+	case "generated_data":
+		return a.generatedData()
+	}
+	return a.Artifact.State(name)
+}
+
+// generatedData returns a copy of the generated data of the wrapped artifact
+// with the metadata of the environment. The type of the map is kept, as
+// post-processors expect the one they get from plugins.
+func (a *buildMetadataArtifact) generatedData() interface{} {
+	data := a.Artifact.State("generated_data")
+	if a.envMetadata == nil {
+		return data
+	}
+
+	switch data := data.(type) {
+	case map[string]interface{}:
+		generatedData := make(map[string]interface{}, len(data)+1)
+		for k, v := range data {
+			generatedData[k] = v
+		}
+		generatedData["PackerBuildMetadata"] = a.envMetadata
+		return generatedData
+	case map[interface{}]interface{}:
+		generatedData := make(map[interface{}]interface{}, len(data)+1)
+		for k, v := range data {
+			generatedData[k] = v
+		}
+		generatedData["PackerBuildMetadata"] = a.envMetadata
+		return generatedData
+	case nil:
+		return map[interface{}]interface{}{
+			"PackerBuildMetadata": a.envMetadata,
+		}
+	}
+	return data
+}
+
 func (b *CoreBuild) GetMetadata() BuildMetadata {
 	metadata := BuildMetadata{
 		PackerVersion: version.FormattedVersion(),
@@ -271,6 +354,15 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		panic("Prepare must be called first")
 	}
 
+	b.report.start()
+	artifacts, err := b.run(ctx, originalUi)
+	b.report.end(artifacts, err)
+
+	return artifacts, err
+}
+
+func (b *CoreBuild) run(ctx context.Context, originalUi packersdk.Ui) ([]packersdk.Artifact, error) {
+
 	// Copy the hooks
 	hooks := make(map[string][]packersdk.Hook)
 	for hookName, hookList := range b.hooks {
@@ -290,15 +382,17 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 			}
 			if b.debug {
 				hookedProvisioners[i] = &HookedProvisioner{
-					&DebuggedProvisioner{Provisioner: p.Provisioner},
-					pConfig,
-					p.PType,
+					Provisioner: &DebuggedProvisioner{Provisioner: p.Provisioner},
+					Config:      pConfig,
+					TypeName:    p.PType,
+					Name:        p.PName,
 				}
 			} else {
 				hookedProvisioners[i] = &HookedProvisioner{
-					p.Provisioner,
-					pConfig,
-					p.PType,
+					Provisioner: p.Provisioner,
+					Config:      pConfig,
+					TypeName:    p.PType,
+					Name:        p.PName,
 				}
 			}
 		}
@@ -309,14 +403,16 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 
 		hooks[packersdk.HookProvision] = append(hooks[packersdk.HookProvision], &ProvisionHook{
 			Provisioners: hookedProvisioners,
+			Report:       b.report,
 		})
 	}
 
 	if b.CleanupProvisioner.PType != "" {
 		hookedCleanupProvisioner := &HookedProvisioner{
-			b.CleanupProvisioner.Provisioner,
-			b.CleanupProvisioner.config,
-			b.CleanupProvisioner.PType,
+			Provisioner: b.CleanupProvisioner.Provisioner,
+			Config:      b.CleanupProvisioner.config,
+			TypeName:    b.CleanupProvisioner.PType,
+			Name:        b.CleanupProvisioner.PName,
 		}
 		hooks[packersdk.HookCleanupProvision] = []packersdk.Hook{&ProvisionHook{
 			Provisioners: []*HookedProvisioner{hookedCleanupProvisioner},
@@ -332,6 +428,12 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		Ui:     originalUi,
 	}
 
+	if b.Conditions != nil {
+		if err := b.Conditions.CheckPreconditions(); err != nil {
+			return nil, err
+		}
+	}
+
 	var ts *TelemetrySpan
 	log.Printf("Running builder: %s", b.BuilderType)
 	if b.BuilderConfig != nil {
@@ -363,6 +465,12 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		return nil, nil
 	}
 
+	if b.Conditions != nil {
+		if err := b.Conditions.CheckPostconditions(builderArtifact); err != nil {
+			return nil, fmt.Errorf("%s\n\nThe artifact of the build was kept: %s", err, builderArtifact.String())
+		}
+	}
+
 	errors := make([]error, 0)
 	keepOriginalArtifact := len(b.PostProcessors) == 0
 
@@ -373,89 +481,26 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 	default:
 	}
 
-	// Run the post-processors
-PostProcessorRunSeqLoop:
-	for _, ppSeq := range b.PostProcessors {
-		priorArtifact := builderArtifact
-		for i, corePP := range ppSeq {
-			ppUi := &TargetedUI{
-				Target: fmt.Sprintf("%s (%s)", b.Name(), corePP.PType),
-				Ui:     originalUi,
-			}
-
-			if corePP.PName == corePP.PType {
-				builderUi.Say(fmt.Sprintf("Running post-processor: %s", corePP.PType))
-			} else {
-				builderUi.Say(fmt.Sprintf("Running post-processor: %s (type %s)", corePP.PName, corePP.PType))
-			}
-			var ts *TelemetrySpan
-			if corePP.config != nil {
-				ts = CheckpointReporter.AddSpan(corePP.PType, "post-processor", corePP.config)
-			} else {
-				ts = CheckpointReporter.AddSpan(corePP.PType, "post-processor", corePP.HCLConfig)
-			}
-			artifact, defaultKeep, forceOverride, err := corePP.PostProcessor.PostProcess(ctx, ppUi, priorArtifact)
-			ts.End(err)
-			if err != nil {
-				errors = append(errors, fmt.Errorf("Post-processor failed: %s", err))
-				continue PostProcessorRunSeqLoop
-			}
-
-			if artifact == nil {
-				log.Println("Nil artifact, halting post-processor chain.")
-				continue PostProcessorRunSeqLoop
-			}
-
-			keep := defaultKeep
-			// When user has not set keep_input_artifact
-			// corePP.keepInputArtifact is nil.
-			// In this case, use the keepDefault provided by the postprocessor.
-			// When user _has_ set keep_input_artifact, go with that instead.
-			// Exception: for postprocessors that will fail/become
-			// useless if keep isn't true, heed forceOverride and keep the
-			// input artifact regardless of user preference.
-			if corePP.KeepInputArtifact != nil {
-				if defaultKeep && *corePP.KeepInputArtifact == false && forceOverride {
-					log.Printf("The %s post-processor forces "+
-						"keep_input_artifact=true to preserve integrity of the"+
-						"build chain. User-set keep_input_artifact=false will be"+
-						"ignored.", corePP.PType)
-				} else {
-					// User overrides default.
-					keep = *corePP.KeepInputArtifact
-				}
-			}
-			if i == 0 {
-				// This is the first post-processor. We handle deleting
-				// previous artifacts a bit different because multiple
-				// post-processors may be using the original and need it.
-				if !keepOriginalArtifact && keep {
-					log.Printf(
-						"Flagging to keep original artifact from post-processor '%s'",
-						corePP.PType)
-					keepOriginalArtifact = true
-				}
-			} else {
-				// We have a prior artifact. If we want to keep it, we append
-				// it to the results list. Otherwise, we destroy it.
-				if keep {
-					artifacts = append(artifacts, priorArtifact)
-				} else {
-					log.Printf("Deleting prior artifact from post-processor '%s'", corePP.PType)
-					if err := priorArtifact.Destroy(); err != nil {
-						log.Printf("Error is %#v", err)
-						errors = append(errors, fmt.Errorf("Failed cleaning up prior artifact: %s; pp is %s", err, corePP.PType))
-					}
-				}
-			}
-
-			priorArtifact = artifact
-		}
+	// Run the post-processors. Each sequence only shares the builder
+	// artifact with the others, so they can run concurrently; results are
+	// still gathered in the order the sequences were defined.
+	results := make([]postProcessorSeqResult, len(b.PostProcessors))
+	var g errgroup.Group
+	if b.postProcessorParallelism < int64(len(b.PostProcessors)) {
+		g.SetLimit(int(max(b.postProcessorParallelism, 1)))
+	}
+	for i, ppSeq := range b.PostProcessors {
+		g.Go(func() error {
+			results[i] = b.runPostProcessorSeq(ctx, originalUi, builderUi, builderArtifact, ppSeq)
+			return nil
+		})
+	}
+	_ = g.Wait()
 
-		// Add on the last artifact to the results
-		if priorArtifact != nil {
-			artifacts = append(artifacts, priorArtifact)
-		}
+	for _, res := range results {
+		keepOriginalArtifact = keepOriginalArtifact || res.keepOriginalArtifact
+		artifacts = append(artifacts, res.artifacts...)
+		errors = append(errors, res.errors...)
 	}
 
 	if keepOriginalArtifact {
@@ -477,6 +522,124 @@ PostProcessorRunSeqLoop:
 	return artifacts, nil
 }
 
+// postProcessorSeqResult is the outcome of a single post-processor sequence.
+type postProcessorSeqResult struct {
+	// artifacts kept or produced by the sequence, in order.
+	artifacts []packersdk.Artifact
+	// keepOriginalArtifact is set when the first post-processor of the
+	// sequence asked to keep the builder artifact.
+	keepOriginalArtifact bool
+	errors               []error
+}
+
+// runPostProcessorSeq runs a sequence of post-processors, each one consuming
+// the artifact of the previous one, starting with the builder artifact.
+func (b *CoreBuild) runPostProcessorSeq(ctx context.Context, originalUi packersdk.Ui, builderUi packersdk.Ui, builderArtifact packersdk.Artifact, ppSeq []CoreBuildPostProcessor) postProcessorSeqResult {
+	res := postProcessorSeqResult{}
+	pluginVersions := b.pluginVersions()
+
+	priorArtifact := builderArtifact
+	for i, corePP := range ppSeq {
+		ppUi := &TargetedUI{
+			Target: fmt.Sprintf("%s (%s)", b.Name(), corePP.PType),
+			Ui:     originalUi,
+		}
+
+		if corePP.PName == corePP.PType {
+			builderUi.Say(fmt.Sprintf("Running post-processor: %s", corePP.PType))
+		} else {
+			builderUi.Say(fmt.Sprintf("Running post-processor: %s (type %s)", corePP.PName, corePP.PType))
+		}
+		var ts *TelemetrySpan
+		if corePP.config != nil {
+			ts = CheckpointReporter.AddSpan(corePP.PType, "post-processor", corePP.config)
+		} else {
+			ts = CheckpointReporter.AddSpan(corePP.PType, "post-processor", corePP.HCLConfig)
+		}
+		step := b.report.addPostProcessor(corePP.PType, corePP.PName)
+		input := &buildMetadataArtifact{Artifact: priorArtifact, pluginVersions: pluginVersions, envMetadata: b.EnvMetadata}
+		artifact, defaultKeep, forceOverride, err := corePP.PostProcessor.PostProcess(ctx, ppUi, input)
+		if artifact == input {
+			artifact = priorArtifact
+		}
+		step.end(err)
+		ts.End(err)
+		if err != nil {
+			res.errors = append(res.errors, fmt.Errorf("Post-processor failed: %s", err))
+			return res
+		}
+
+		if artifact == nil {
+			log.Println("Nil artifact, halting post-processor chain.")
+			return res
+		}
+
+		keep := defaultKeep
+		// When user has not set keep_input_artifact
+		// corePP.keepInputArtifact is nil.
+		// In this case, use the keepDefault provided by the postprocessor.
+		// When user _has_ set keep_input_artifact, go with that instead.
+		// Exception: for postprocessors that will fail/become
+		// useless if keep isn't true, heed forceOverride and keep the
+		// input artifact regardless of user preference.
+		if corePP.KeepInputArtifact != nil {
+			if defaultKeep && *corePP.KeepInputArtifact == false && forceOverride {
+				log.Printf("The %s post-processor forces "+
+					"keep_input_artifact=true to preserve integrity of the"+
+					"build chain. User-set keep_input_artifact=false will be"+
+					"ignored.", corePP.PType)
+			} else {
+				// User overrides default.
+				keep = *corePP.KeepInputArtifact
+			}
+		}
+		if i == 0 {
+			// This is the first post-processor. We handle deleting
+			// previous artifacts a bit different because multiple
+			// post-processors may be using the original and need it.
+			if keep {
+				log.Printf(
+					"Flagging to keep original artifact from post-processor '%s'",
+					corePP.PType)
+				res.keepOriginalArtifact = true
+			}
+		} else {
+			// We have a prior artifact. If we want to keep it, we append
+			// it to the results list. Otherwise, we destroy it.
+			if keep {
+				res.artifacts = append(res.artifacts, priorArtifact)
+			} else {
+				log.Printf("Deleting prior artifact from post-processor '%s'", corePP.PType)
+				if err := priorArtifact.Destroy(); err != nil {
+					log.Printf("Error is %#v", err)
+					res.errors = append(res.errors, fmt.Errorf("Failed cleaning up prior artifact: %s; pp is %s", err, corePP.PType))
+				}
+			}
+		}
+
+		priorArtifact = artifact
+	}
+
+	// Add on the last artifact to the results
+	if priorArtifact != nil {
+		res.artifacts = append(res.artifacts, priorArtifact)
+	}
+
+	return res
+}
+
+// SetReport sets the report in which the outcome of each step of the build
+// is recorded. A nil report disables reporting.
+func (b *CoreBuild) SetReport(report *BuildReport) {
+	b.report = report
+}
+
+// SetPostProcessorParallelism sets how many post-processor sequences can run
+// at the same time. By default sequences run one after the other.
+func (b *CoreBuild) SetPostProcessorParallelism(val int64) {
+	b.postProcessorParallelism = val
+}
+
 func (b *CoreBuild) SetDebug(val bool) {
 	if b.prepareCalled {
 		panic("prepare has already been called")
@@ -0,0 +1,141 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package packer
+
+import (
+	"sync"
+	"time"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// BuildReport records the timing and outcome of every step of a CoreBuild
+// run, so it can be written as a structured document once the build is over.
+//
+// All methods are safe to call on a nil *BuildReport, in which case nothing
+// is recorded.
+type BuildReport struct {
+	Name            string                `json:"name"`
+	BuilderType     string                `json:"builder_type"`
+	StartTime       time.Time             `json:"start_time"`
+	EndTime         time.Time             `json:"end_time"`
+	DurationSeconds float64               `json:"duration_seconds"`
+	Error           string                `json:"error,omitempty"`
+	Provisioners    []*BuildReportStep    `json:"provisioners"`
+	PostProcessors  []*BuildReportStep    `json:"post_processors"`
+	Artifacts       []BuildReportArtifact `json:"artifacts"`
+
+	l sync.Mutex
+}
+
+// BuildReportStep is the record of a single provisioner or post-processor
+// run.
+type BuildReportStep struct {
+	Type            string    `json:"type"`
+	Name            string    `json:"name"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// BuildReportArtifact describes an artifact returned by a build.
+type BuildReportArtifact struct {
+	BuilderId string   `json:"builder_id"`
+	Id        string   `json:"id"`
+	Files     []string `json:"files"`
+}
+
+// NewBuildReport returns an empty report for the given build.
+func NewBuildReport(b *CoreBuild) *BuildReport {
+	return &BuildReport{
+		Name:           b.Name(),
+		BuilderType:    b.BuilderType,
+		Provisioners:   []*BuildReportStep{},
+		PostProcessors: []*BuildReportStep{},
+		Artifacts:      []BuildReportArtifact{},
+	}
+}
+
+func (r *BuildReport) start() {
+	if r == nil {
+		return
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.StartTime = time.Now().UTC()
+}
+
+func (r *BuildReport) end(artifacts []packersdk.Artifact, err error) {
+	if r == nil {
+		return
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.EndTime = time.Now().UTC()
+	r.DurationSeconds = r.EndTime.Sub(r.StartTime).Seconds()
+	if err != nil {
+		r.Error = err.Error()
+	}
+	for _, artifact := range artifacts {
+		if artifact == nil {
+			continue
+		}
+		files := artifact.Files()
+		if files == nil {
+			files = []string{}
+		}
+		r.Artifacts = append(r.Artifacts, BuildReportArtifact{
+			BuilderId: artifact.BuilderId(),
+			Id:        artifact.Id(),
+			Files:     files,
+		})
+	}
+}
+
+// addProvisioner starts recording a provisioner step.
+func (r *BuildReport) addProvisioner(pType, pName string) *BuildReportStep {
+	if r == nil {
+		return nil
+	}
+	step := newBuildReportStep(pType, pName)
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.Provisioners = append(r.Provisioners, step)
+	return step
+}
+
+// addPostProcessor starts recording a post-processor step.
+func (r *BuildReport) addPostProcessor(pType, pName string) *BuildReportStep {
+	if r == nil {
+		return nil
+	}
+	step := newBuildReportStep(pType, pName)
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.PostProcessors = append(r.PostProcessors, step)
+	return step
+}
+
+func newBuildReportStep(pType, pName string) *BuildReportStep {
+	if pName == "" {
+		pName = pType
+	}
+	return &BuildReportStep{
+		Type:      pType,
+		Name:      pName,
+		StartTime: time.Now().UTC(),
+	}
+}
+
+func (s *BuildReportStep) end(err error) {
+	if s == nil {
+		return
+	}
+	s.EndTime = time.Now().UTC()
+	s.DurationSeconds = s.EndTime.Sub(s.StartTime).Seconds()
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
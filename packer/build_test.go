@@ -5,8 +5,13 @@ package packer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -365,6 +370,36 @@ func TestBuild_Run(t *testing.T) {
 	}
 }
 
+func TestBuild_Run_Report(t *testing.T) {
+	ui := testUi()
+
+	build := testBuild()
+	report := NewBuildReport(build)
+	build.SetReport(report)
+	build.Prepare()
+	ctx := context.Background()
+	artifacts, err := build.Run(ctx, ui)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if report.StartTime.IsZero() || report.EndTime.IsZero() {
+		t.Fatalf("build times should be recorded: %#v", report)
+	}
+	if len(report.Provisioners) != 1 || report.Provisioners[0].Type != "mock-provisioner" {
+		t.Fatalf("bad provisioners: %#v", report.Provisioners)
+	}
+	if len(report.PostProcessors) != 1 || report.PostProcessors[0].Name != "testPPName" {
+		t.Fatalf("bad post-processors: %#v", report.PostProcessors)
+	}
+	if len(report.Artifacts) != len(artifacts) {
+		t.Fatalf("expected %d artifacts, got %#v", len(artifacts), report.Artifacts)
+	}
+	if report.Artifacts[1].Id != "pp" {
+		t.Fatalf("bad artifact: %#v", report.Artifacts[1])
+	}
+}
+
 func TestBuild_Run_Artifacts(t *testing.T) {
 	ui := testUi()
 
@@ -555,6 +590,158 @@ func TestBuild_Run_Artifacts(t *testing.T) {
 	}
 }
 
+// barrierPostProcessor only returns once all the post-processors sharing its
+// barrier have started, which requires them to run concurrently.
+type barrierPostProcessor struct {
+	MockPostProcessor
+	barrier *sync.WaitGroup
+}
+
+func (p *barrierPostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, a packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	p.barrier.Done()
+	done := make(chan struct{})
+	go func() {
+		p.barrier.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		return nil, false, false, fmt.Errorf("post-processors did not run concurrently")
+	}
+	return p.MockPostProcessor.PostProcess(ctx, ui, a)
+}
+
+func TestBuild_Run_ParallelPostProcessors(t *testing.T) {
+	ui := testUi()
+
+	barrier := &sync.WaitGroup{}
+	barrier.Add(2)
+
+	build := testBuild()
+	build.SetPostProcessorParallelism(2)
+	build.PostProcessors = [][]CoreBuildPostProcessor{
+		{
+			{&barrierPostProcessor{MockPostProcessor{ArtifactId: "pp1"}, barrier}, "pp", "testPPName", cty.Value{}, make(map[string]interface{}), boolPointer(false)},
+		},
+		{
+			{&barrierPostProcessor{MockPostProcessor{ArtifactId: "pp2"}, barrier}, "pp", "testPPName", cty.Value{}, make(map[string]interface{}), boolPointer(true)},
+			{&MockPostProcessor{ArtifactId: "pp3"}, "pp", "testPPName", cty.Value{}, make(map[string]interface{}), boolPointer(false)},
+		},
+	}
+
+	build.Prepare()
+	artifacts, err := build.Run(context.Background(), ui)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The original artifact is kept since the second sequence asked for it,
+	// and results are in the order the sequences were defined.
+	expectedIds := []string{"b", "pp1", "pp3"}
+	artifactIds := make([]string, len(artifacts))
+	for i, artifact := range artifacts {
+		artifactIds[i] = artifact.Id()
+	}
+
+	if !reflect.DeepEqual(artifactIds, expectedIds) {
+		t.Fatalf("unexpected ids: %#v", artifactIds)
+	}
+}
+
+type testBuildConditions struct {
+	preErr, postErr error
+	artifact        packersdk.Artifact
+}
+
+func (c *testBuildConditions) CheckPreconditions() error { return c.preErr }
+
+func (c *testBuildConditions) CheckPostconditions(a packersdk.Artifact) error {
+	c.artifact = a
+	return c.postErr
+}
+
+func TestBuild_Run_Conditions(t *testing.T) {
+	ctx := context.Background()
+
+	build := testBuild()
+	build.Conditions = &testBuildConditions{preErr: errors.New("precondition failed")}
+	build.Prepare()
+	if _, err := build.Run(ctx, testUi()); err == nil || err.Error() != "precondition failed" {
+		t.Fatalf("expected the precondition error, got %v", err)
+	}
+	if build.Builder.(*packersdk.MockBuilder).RunCalled {
+		t.Fatal("the builder should not run when a precondition fails")
+	}
+
+	build = testBuild()
+	conditions := &testBuildConditions{postErr: errors.New("postcondition failed")}
+	build.Conditions = conditions
+	build.Prepare()
+	_, err := build.Run(ctx, testUi())
+	if err == nil || !strings.HasPrefix(err.Error(), "postcondition failed") {
+		t.Fatalf("expected the postcondition error, got %v", err)
+	}
+	if conditions.artifact == nil || conditions.artifact.Id() != "b" {
+		t.Fatalf("postconditions should get the artifact of the builder, got %#v", conditions.artifact)
+	}
+	if build.PostProcessors[0][0].PostProcessor.(*MockPostProcessor).PostProcessCalled {
+		t.Fatal("post-processors should not run when a postcondition fails")
+	}
+
+	build = testBuild()
+	build.Conditions = &testBuildConditions{}
+	build.Prepare()
+	if _, err := build.Run(ctx, testUi()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestBuildMetadataArtifact_generatedData(t *testing.T) {
+	envMetadata := map[string]interface{}{
+		"vcs": map[string]interface{}{"type": "git"},
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected interface{}
+	}{
+		{
+			name:     "no generated data",
+			data:     nil,
+			expected: map[interface{}]interface{}{"PackerBuildMetadata": envMetadata},
+		},
+		{
+			name:     "plugin generated data",
+			data:     map[interface{}]interface{}{"ID": "Null"},
+			expected: map[interface{}]interface{}{"ID": "Null", "PackerBuildMetadata": envMetadata},
+		},
+		{
+			name:     "in-process generated data",
+			data:     map[string]interface{}{"ID": "Null"},
+			expected: map[string]interface{}{"ID": "Null", "PackerBuildMetadata": envMetadata},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &packersdk.MockArtifact{StateValues: map[string]interface{}{"generated_data": tt.data}}
+			artifact := &buildMetadataArtifact{Artifact: source, envMetadata: envMetadata}
+			if got := artifact.State("generated_data"); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %#v, got %#v", tt.expected, got)
+			}
+		})
+	}
+
+	// The generated data is left as is without metadata.
+	source := &packersdk.MockArtifact{StateValues: map[string]interface{}{"generated_data": map[string]interface{}{"ID": "Null"}}}
+	artifact := &buildMetadataArtifact{Artifact: source}
+	if got := artifact.State("generated_data"); !reflect.DeepEqual(got, map[string]interface{}{"ID": "Null"}) {
+		t.Errorf("unexpected generated data %#v", got)
+	}
+}
+
 func TestBuild_RunBeforePrepare(t *testing.T) {
 	defer func() {
 		p := recover()
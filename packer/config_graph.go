@@ -0,0 +1,111 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package packer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Kinds of edges of a ConfigGraph.
+const (
+	// ConfigGraphDependsOn links a block to a block its configuration
+	// references.
+	ConfigGraphDependsOn = "depends_on"
+	// ConfigGraphContains links a build to its sources, provisioners and
+	// post-processors.
+	ConfigGraphContains = "contains"
+	// ConfigGraphNext links a provisioner or a post-processor to the one
+	// that runs after it.
+	ConfigGraphNext = "next"
+)
+
+type ConfigGraphOptions struct {
+	// IncludeVariables adds the input variables, and the references to them,
+	// to the graph.
+	IncludeVariables bool
+}
+
+// ConfigGrapher is implemented by configurations that can describe how their
+// blocks depend on each other.
+type ConfigGrapher interface {
+	ConfigGraph(ConfigGraphOptions) (*ConfigGraph, hcl.Diagnostics)
+}
+
+// ConfigGraph is the graph of the blocks of a configuration.
+type ConfigGraph struct {
+	Nodes []ConfigGraphNode `json:"nodes"`
+	Edges []ConfigGraphEdge `json:"edges"`
+}
+
+type ConfigGraphNode struct {
+	// ID identifies the node, for example `data.http.example` or
+	// `build.docker.provisioner[0]`.
+	ID string `json:"id"`
+	// Type is the type of block: variable, local, data, communicator, source, build,
+	// provisioner, error-cleanup-provisioner or post-processor.
+	Type string `json:"type"`
+	// Label is a human readable description of the node.
+	Label string `json:"label"`
+}
+
+type ConfigGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// DOT returns the graph in the Graphviz DOT language.
+func (g *ConfigGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	b.WriteString("\tcompound = \"true\"\n")
+	b.WriteString("\tnewrank = \"true\"\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%q [label=%q, shape=%q]\n", n.ID, n.Label, dotShape(n.Type))
+	}
+	for _, e := range g.Edges {
+		style := "solid"
+		if e.Kind == ConfigGraphDependsOn {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q, style=%q]\n", e.From, e.To, e.Kind, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotShape(nodeType string) string {
+	switch nodeType {
+	case "variable", "local":
+		return "note"
+	case "data":
+		return "cylinder"
+	case "build":
+		return "box3d"
+	}
+	return "box"
+}
+
+// Mermaid returns the graph as a Mermaid flowchart.
+func (g *ConfigGraph) Mermaid() string {
+	// Mermaid IDs cannot contain most punctuation, so nodes are numbered.
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[n.ID], strings.ReplaceAll(n.Label, `"`, "#quot;"))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Kind == ConfigGraphDependsOn {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "    %s %s|%s| %s\n", ids[e.From], arrow, e.Kind, ids[e.To])
+	}
+	return b.String()
+}
@@ -41,6 +41,8 @@ type Core struct {
 	version    string
 	secrets    []string
 
+	envMetadata map[string]interface{}
+
 	except []string
 	only   []string
 }
@@ -154,7 +156,8 @@ func (c *Core) DetectPluginBinaries() hcl.Diagnostics {
 	return diags
 }
 
-func (c *Core) Initialize(_ InitializeOptions) hcl.Diagnostics {
+func (c *Core) Initialize(opts InitializeOptions) hcl.Diagnostics {
+	c.envMetadata = opts.EnvMetadata
 	err := c.initialize()
 	if err != nil {
 		return hcl.Diagnostics{
@@ -597,6 +600,7 @@ func (c *Core) Build(n string) (*CoreBuild, error) {
 		TemplatePath:       c.Template.Path,
 		Variables:          c.variables,
 		SensitiveVars:      sensitiveVars,
+		EnvMetadata:        c.envMetadata,
 	}
 
 	//configBuilder.Name is left uninterpolated so we must check against
@@ -668,6 +672,10 @@ func (c *Core) InspectConfig(opts InspectConfigOptions) int {
 	// Convenience...
 	ui := opts.Ui
 	tpl := c.Template
+	if opts.Format != "" {
+		ui.Error(fmt.Sprintf("The %s format is only supported for HCL2 templates", opts.Format))
+		return 1
+	}
 	ui.Say("Packer Inspect: JSON mode")
 
 	// Description
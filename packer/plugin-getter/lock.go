@@ -0,0 +1,342 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package plugingetter
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockFileName is the name of the dependency lock file written by `packer
+// init` next to the templates.
+const LockFileName = ".packer.lock.hcl"
+
+const lockFileHeader = `# This file is maintained automatically by "packer init".
+# Manual edits may be lost in future updates.
+`
+
+// LockFile records the exact version of every required plugin, the checksums
+// of its release archives for every platform, and the checksum of its binary
+// for each platform it was installed on, so that every user of a template
+// runs the same plugins.
+type LockFile struct {
+	// Plugins are indexed by plugin source, i.e. github.com/hashicorp/amazon.
+	Plugins map[string]*LockedPlugin
+}
+
+// LockedPlugin is the locked state of a single plugin.
+type LockedPlugin struct {
+	Source string `hcl:"source,label"`
+	// Version is the exact version selected, i.e. 1.2.3.
+	Version string `hcl:"version"`
+	// Constraints are the version constraints of the template at the time
+	// the version was selected.
+	Constraints string `hcl:"constraints,optional"`
+	// Hashes maps an os_arch platform to the "sha256:<hex>" checksum of the
+	// plugin binary for that platform.
+	Hashes map[string]string `hcl:"hashes,optional"`
+	// ZipHashes maps an os_arch platform to the "sha256:<hex>" checksum of the
+	// release archive of the plugin for that platform, as published in the
+	// checksum file of the release. They allow to verify an installation on a
+	// platform the lock was not written on.
+	ZipHashes map[string]string `hcl:"zip_hashes,optional"`
+}
+
+// LockFilePath returns the path of the lock file for a template, which can be
+// either a file or a directory.
+func LockFilePath(templatePath string) string {
+	if fi, err := os.Stat(templatePath); err == nil && fi.IsDir() {
+		return filepath.Join(templatePath, LockFileName)
+	}
+	return filepath.Join(filepath.Dir(templatePath), LockFileName)
+}
+
+// ReadLockFile parses the lock file at path. A missing file results in an
+// empty lock and no error; use Exists to know whether the file was found.
+func ReadLockFile(path string) (*LockFile, error) {
+	lock := &LockFile{Plugins: map[string]*LockedPlugin{}}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return lock, nil
+	}
+
+	f, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var content struct {
+		Plugins []*LockedPlugin `hcl:"plugin,block"`
+	}
+	if diags := gohcl.DecodeBody(f.Body, nil, &content); diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, p := range content.Plugins {
+		if _, exists := lock.Plugins[p.Source]; exists {
+			return nil, fmt.Errorf("%s: duplicate plugin %q", path, p.Source)
+		}
+		if p.Hashes == nil {
+			p.Hashes = map[string]string{}
+		}
+		if p.ZipHashes == nil {
+			p.ZipHashes = map[string]string{}
+		}
+		lock.Plugins[p.Source] = p
+	}
+
+	return lock, nil
+}
+
+// Exists tells whether the lock has any plugin recorded.
+func (l *LockFile) Exists() bool {
+	return len(l.Plugins) > 0
+}
+
+// Retain drops from the lock the plugins that are not in reqs, i.e. the ones
+// removed from the required_plugins of the template.
+func (l *LockFile) Retain(reqs Requirements) {
+	required := make(map[string]bool, len(reqs))
+	for _, pr := range reqs {
+		required[pr.Identifier.String()] = true
+	}
+	for source := range l.Plugins {
+		if !required[source] {
+			delete(l.Plugins, source)
+		}
+	}
+}
+
+// Write serialises the lock file to path, plugins being sorted by source.
+func (l *LockFile) Write(path string) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	sources := make([]string, 0, len(l.Plugins))
+	for source := range l.Plugins {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for i, source := range sources {
+		p := l.Plugins[source]
+		if i > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("plugin", []string{source}).Body()
+		block.SetAttributeValue("version", cty.StringVal(p.Version))
+		if p.Constraints != "" {
+			block.SetAttributeValue("constraints", cty.StringVal(p.Constraints))
+		}
+		for _, attr := range []struct {
+			name   string
+			hashes map[string]string
+		}{
+			{"hashes", p.Hashes},
+			{"zip_hashes", p.ZipHashes},
+		} {
+			hashes := map[string]cty.Value{}
+			for platform, hash := range attr.hashes {
+				hashes[platform] = cty.StringVal(hash)
+			}
+			if len(hashes) > 0 {
+				block.SetAttributeValue(attr.name, cty.ObjectVal(hashes))
+			}
+		}
+	}
+
+	content := append([]byte(lockFileHeader+"\n"), hclwrite.Format(f.Bytes())...)
+	return os.WriteFile(path, content, 0644)
+}
+
+// Platform returns the key under which the binary hashes are stored for the
+// OS and architecture of opts.
+func (opts BinaryInstallationOptions) Platform() string {
+	return opts.OS + "_" + opts.ARCH
+}
+
+// HashBinary returns the lock-file representation of the checksum of the
+// plugin binary at binaryPath.
+func HashBinary(binaryPath string) (string, error) {
+	f, err := os.Open(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	checksummer := Checksummer{Type: "sha256", Hash: sha256.New()}
+	sum, err := checksummer.Sum(f)
+	if err != nil {
+		return "", err
+	}
+	return checksummer.Type + ":" + Checksum(sum).String(), nil
+}
+
+// Record stores the version and binary hash of an installation for the
+// current platform, along with the release archive hashes of all platforms
+// when zipHashes is set. Hashes already recorded are kept if the version did
+// not change.
+func (l *LockFile) Record(pr *Requirement, install *Installation, opts BinaryInstallationOptions, zipHashes map[string]string) error {
+	hash, err := HashBinary(install.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %s", install.BinaryPath, err)
+	}
+
+	source := pr.Identifier.String()
+	version := strings.TrimPrefix(install.Version, "v")
+	locked, ok := l.Plugins[source]
+	if !ok || locked.Version != version {
+		locked = &LockedPlugin{
+			Source:    source,
+			Version:   version,
+			Hashes:    map[string]string{},
+			ZipHashes: map[string]string{},
+		}
+		l.Plugins[source] = locked
+	}
+	locked.Constraints = pr.VersionConstraints.String()
+	locked.Hashes[opts.Platform()] = hash
+	for platform, zipHash := range zipHashes {
+		locked.ZipHashes[platform] = zipHash
+	}
+
+	return nil
+}
+
+// Verify checks that the binary of an installation is the one recorded in
+// the lock for the current platform. A missing hash for the platform is an
+// error: packer init records it once the release archive of the plugin was
+// verified against the lock.
+func (lp *LockedPlugin) Verify(install *Installation, opts BinaryInstallationOptions) error {
+	if strings.TrimPrefix(install.Version, "v") != lp.Version {
+		return fmt.Errorf("installed version %s of %s does not match the locked version %s",
+			install.Version, lp.Source, lp.Version)
+	}
+
+	expected, ok := lp.Hashes[opts.Platform()]
+	if !ok {
+		return fmt.Errorf("no checksum of %s is recorded for %s. Run packer init to verify the "+
+			"plugin against the lock file and record it", lp.Source, opts.Platform())
+	}
+
+	actual, err := HashBinary(install.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %s", install.BinaryPath, err)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum of %s does not match the lock file.\nExpected: %s\nGot     : %s",
+			install.BinaryPath, expected, actual)
+	}
+	return nil
+}
+
+// VersionConstraints returns constraints only matching the locked version.
+func (lp *LockedPlugin) VersionConstraints() (goversion.Constraints, error) {
+	return goversion.NewConstraint("= " + lp.Version)
+}
+
+// ReleaseChecksums returns the lock-file representation of the checksums of
+// the release archives of a version of the plugin, indexed by os_arch
+// platform. They are read from the checksum file of the release, i.e. its
+// SHA256SUMS, with the first getter able to serve it. Archives built for a
+// protocol version this Packer cannot run are left out.
+func (pr *Requirement) ReleaseChecksums(opts InstallOptions, version *goversion.Version) (map[string]string, error) {
+	var errs *multierror.Error
+	for _, getter := range opts.Getters {
+		for _, checksummer := range opts.Checksummers {
+			getOpts := GetOptions{
+				PluginRequirement:         pr,
+				BinaryInstallationOptions: opts.BinaryInstallationOptions,
+				version:                   version,
+			}
+			checksumFile, err := getter.Get(checksummer.Type, getOpts)
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("could not get %s checksum file for %s version %s: %w",
+					checksummer.Type, pr.Identifier, version, err))
+				continue
+			}
+			entries, err := ParseChecksumFileEntries(checksumFile)
+			_ = checksumFile.Close()
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("could not parse %s checksum file: %w", checksummer.Type, err))
+				continue
+			}
+
+			var releaseEntries []ChecksumFileEntry
+			for _, entry := range entries {
+				if filepath.Ext(entry.Filename) == JSONExtension {
+					continue
+				}
+				if err := getter.Init(pr, &entry); err != nil {
+					log.Printf("[TRACE] ignoring checksum of %s: %s", entry.Filename, err)
+					continue
+				}
+				if strings.TrimPrefix(entry.BinVersion, "v") != version.String() {
+					log.Printf("[TRACE] ignoring checksum of %s: not version %s", entry.Filename, version)
+					continue
+				}
+				releaseEntries = append(releaseEntries, entry)
+			}
+			if len(releaseEntries) == 0 {
+				continue
+			}
+
+			// Some getters download the manifest of the release to validate
+			// an entry, so only one entry is validated, preferably the one of
+			// this platform. The protocol version of the others is checked
+			// when their file name carries it.
+			validated := releaseEntries[0]
+			for _, entry := range releaseEntries {
+				if entry.Os == opts.OS && entry.Arch == opts.ARCH {
+					validated = entry
+				}
+			}
+			validateOpts := opts.BinaryInstallationOptions
+			validateOpts.OS, validateOpts.ARCH = validated.Os, validated.Arch
+			if err := getter.Validate(getOpts, version.String(), validateOpts, &validated); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("could not validate release %s of %s: %w", version, pr.Identifier, err))
+				continue
+			}
+
+			hashes := map[string]string{}
+			for _, entry := range releaseEntries {
+				if entry.ProtVersion != "" {
+					if err := opts.CheckProtocolVersion(entry.ProtVersion); err != nil {
+						log.Printf("[TRACE] ignoring checksum of %s: %s", entry.Filename, err)
+						continue
+					}
+				}
+				cs, err := checksummer.ParseChecksum(strings.NewReader(entry.Checksum))
+				if err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("could not parse %s checksum of %s: %w",
+						checksummer.Type, entry.Filename, err))
+					continue
+				}
+				platformOpts := opts.BinaryInstallationOptions
+				platformOpts.OS, platformOpts.ARCH = entry.Os, entry.Arch
+				hashes[platformOpts.Platform()] = checksummer.Type + ":" + Checksum(cs).String()
+			}
+			if len(hashes) > 0 {
+				return hashes, nil
+			}
+		}
+	}
+
+	if errs.Len() == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("no release checksum found for %s version %s", pr.Identifier, version))
+	}
+	return nil, errs
+}
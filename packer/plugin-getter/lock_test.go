@@ -0,0 +1,214 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package plugingetter
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+)
+
+func TestLockFile_roundTrip(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "packer-plugin-amazon_v1.2.3_x5.0_linux_amd64")
+	if err := os.WriteFile(binary, []byte("amazon plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	lockPath := filepath.Join(dir, LockFileName)
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("reading a missing lock file should not fail: %s", err)
+	}
+	if lock.Exists() {
+		t.Fatal("lock should be empty")
+	}
+
+	identifier, err := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &Requirement{
+		Identifier:         identifier,
+		VersionConstraints: version.MustConstraints(version.NewConstraint(">= 1.0.0")),
+	}
+	install := &Installation{BinaryPath: binary, Version: "v1.2.3"}
+	linux := BinaryInstallationOptions{OS: "linux", ARCH: "amd64"}
+	darwin := BinaryInstallationOptions{OS: "darwin", ARCH: "arm64"}
+
+	zipHashes := map[string]string{
+		"linux_amd64":  "sha256:1337c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		"darwin_arm64": "sha256:ea1edb6d2e0d6d2c8b9e3d1ae0b1f5a9c6c4e1b2a3d4c5b6a7980f1e2d3c4b5a",
+	}
+	if err := lock.Record(req, install, linux, zipHashes); err != nil {
+		t.Fatalf("Record failed: %s", err)
+	}
+	if err := lock.Write(lockPath); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+
+	content, _ := os.ReadFile(lockPath)
+	if !strings.Contains(string(content), `plugin "github.com/hashicorp/amazon"`) {
+		t.Fatalf("unexpected lock file content:\n%s", content)
+	}
+
+	read, err := ReadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("ReadLockFile failed: %s", err)
+	}
+	if diff := cmp.Diff(lock, read); diff != "" {
+		t.Fatalf("lock file changed after a round trip: %s", diff)
+	}
+
+	locked := read.Plugins["github.com/hashicorp/amazon"]
+	if err := locked.Verify(install, linux); err != nil {
+		t.Fatalf("Verify should succeed: %s", err)
+	}
+	if diff := cmp.Diff(zipHashes, locked.ZipHashes); diff != "" {
+		t.Fatalf("unexpected zip hashes: %s", diff)
+	}
+	if err := locked.Verify(install, darwin); err == nil {
+		t.Fatal("Verify should fail without a hash for the platform")
+	}
+	if err := locked.Verify(&Installation{BinaryPath: binary, Version: "v1.2.4"}, linux); err == nil {
+		t.Fatal("Verify should fail for another version")
+	}
+
+	if err := os.WriteFile(binary, []byte("tampered plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := locked.Verify(install, linux); err == nil {
+		t.Fatal("Verify should fail for a modified binary")
+	}
+}
+
+func TestLockFile_Retain(t *testing.T) {
+	lock := &LockFile{Plugins: map[string]*LockedPlugin{
+		"github.com/hashicorp/amazon": {Source: "github.com/hashicorp/amazon", Version: "1.2.3"},
+		"github.com/hashicorp/docker": {Source: "github.com/hashicorp/docker", Version: "1.0.0"},
+	}}
+	identifier, err := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Retain(Requirements{{Identifier: identifier}})
+	if _, ok := lock.Plugins["github.com/hashicorp/docker"]; ok || len(lock.Plugins) != 1 {
+		t.Fatalf("only the amazon plugin should be kept, got %v", lock.Plugins)
+	}
+
+	lock.Retain(nil)
+	if lock.Exists() {
+		t.Fatalf("no plugin should be kept, got %v", lock.Plugins)
+	}
+}
+
+func TestRequirement_ReleaseChecksums(t *testing.T) {
+	identifier, err := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := &Requirement{Identifier: identifier}
+
+	getter := &mockPluginGetter{
+		Name: "github.com",
+		ChecksumFileEntries: map[string][]ChecksumFileEntry{
+			"1.2.3": {
+				{
+					Filename: "packer-plugin-amazon_v1.2.3_x5.0_linux_amd64.zip",
+					Checksum: "1337c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				},
+				{
+					Filename: "packer-plugin-amazon_v1.2.3_x5.0_darwin_arm64.zip",
+					Checksum: "ea1edb6d2e0d6d2c8b9e3d1ae0b1f5a9c6c4e1b2a3d4c5b6a7980f1e2d3c4b5a",
+				},
+				{
+					// This protocol version cannot run with this Packer.
+					Filename: "packer-plugin-amazon_v1.2.3_x4.0_windows_amd64.zip",
+					Checksum: "0000000000000000000000000000000000000000000000000000000000000000",
+				},
+				{
+					Filename: "packer-plugin-amazon_v1.2.3_x5.0_linux_amd64_manifest.json",
+					Checksum: "1111111111111111111111111111111111111111111111111111111111111111",
+				},
+			},
+		},
+	}
+	opts := InstallOptions{
+		Getters: []Getter{getter},
+		BinaryInstallationOptions: BinaryInstallationOptions{
+			APIVersionMajor: "5", APIVersionMinor: "0",
+			OS: "linux", ARCH: "amd64",
+			Checksummers: []Checksummer{{Type: "sha256", Hash: sha256.New()}},
+		},
+	}
+
+	hashes, err := pr.ReleaseChecksums(opts, version.Must(version.NewVersion("1.2.3")))
+	if err != nil {
+		t.Fatalf("ReleaseChecksums failed: %s", err)
+	}
+	expected := map[string]string{
+		"linux_amd64":  "sha256:1337c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		"darwin_arm64": "sha256:ea1edb6d2e0d6d2c8b9e3d1ae0b1f5a9c6c4e1b2a3d4c5b6a7980f1e2d3c4b5a",
+	}
+	if diff := cmp.Diff(expected, hashes); diff != "" {
+		t.Fatalf("unexpected release checksums: %s", diff)
+	}
+
+	if _, err := pr.ReleaseChecksums(opts, version.Must(version.NewVersion("1.2.4"))); err == nil {
+		t.Fatal("ReleaseChecksums should fail without a checksum file")
+	}
+
+	// The manifest of the release can only be read once: it must be fetched
+	// once for the whole release, not for every platform.
+	releases := &mockPluginGetter{
+		Name: "releases.hashicorp.com",
+		ChecksumFileEntries: map[string][]ChecksumFileEntry{
+			"1.2.3": {
+				{
+					Filename: "packer-plugin-amazon_1.2.3_linux_amd64.zip",
+					Checksum: "1337c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				},
+				{
+					Filename: "packer-plugin-amazon_1.2.3_darwin_arm64.zip",
+					Checksum: "ea1edb6d2e0d6d2c8b9e3d1ae0b1f5a9c6c4e1b2a3d4c5b6a7980f1e2d3c4b5a",
+				},
+			},
+		},
+		Manifest: map[string]io.ReadCloser{
+			"github.com/hashicorp/packer-plugin-amazon/packer-plugin-amazon_1.2.3_linux_amd64_manifest.json": manifestFile(map[string]map[string]string{
+				"metadata": {"protocol_version": "5.0"},
+			}),
+		},
+	}
+	opts.Getters = []Getter{releases}
+	hashes, err = pr.ReleaseChecksums(opts, version.Must(version.NewVersion("1.2.3")))
+	if err != nil {
+		t.Fatalf("ReleaseChecksums failed: %s", err)
+	}
+	if diff := cmp.Diff(expected, hashes); diff != "" {
+		t.Fatalf("unexpected release checksums: %s", diff)
+	}
+}
+
+func TestLockFilePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "build.pkr.hcl")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{dir, file} {
+		if got := LockFilePath(path); got != filepath.Join(dir, LockFileName) {
+			t.Errorf("LockFilePath(%q) = %q", path, got)
+		}
+	}
+}
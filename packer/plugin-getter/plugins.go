@@ -634,6 +634,53 @@ func ParseChecksumFileEntries(f io.Reader) ([]ChecksumFileEntry, error) {
 	return entries, json.NewDecoder(f).Decode(&entries)
 }
 
+// ListReleases returns the versions of the plugin published by the first
+// getter able to list them, sorted in increasing order. Version constraints
+// are not applied, so callers can compare the latest release with the latest
+// allowed one.
+func (pr *Requirement) ListReleases(opts InstallOptions) (goversion.Collection, error) {
+	var errs *multierror.Error
+	for _, getter := range opts.Getters {
+		releasesFile, err := getter.Get("releases", GetOptions{
+			PluginRequirement:         pr,
+			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		})
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			log.Printf("[TRACE] %s", err.Error())
+			continue
+		}
+
+		releases, err := ParseReleases(releasesFile)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not parse release: %w", err))
+			continue
+		}
+
+		versions := goversion.Collection{}
+		for _, release := range releases {
+			v, err := goversion.NewVersion(release.Version)
+			if err != nil {
+				log.Printf("[TRACE] could not parse release version %s, ignoring it: %s", release.Version, err)
+				continue
+			}
+			versions = append(versions, v)
+		}
+		if len(versions) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("no release found"))
+			continue
+		}
+
+		sort.Sort(versions)
+		return versions, nil
+	}
+
+	if errs.Len() == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("no getter could list releases of %s", pr.Identifier))
+	}
+	return nil, errs
+}
+
 func (pr *Requirement) InstallLatest(opts InstallOptions) (*Installation, error) {
 
 	getters := opts.Getters
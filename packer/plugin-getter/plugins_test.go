@@ -782,6 +782,42 @@ echo '{"version":"v2.10.0","api_version":"x6.0"}'`,
 	}
 }
 
+func TestRequirement_ListReleases(t *testing.T) {
+	identifier, err := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := &Requirement{
+		Identifier:         identifier,
+		VersionConstraints: version.MustConstraints(version.NewConstraint("~> 1.0")),
+	}
+
+	getter := &mockPluginGetter{
+		Releases: []Release{
+			{Version: "v1.2.3"},
+			{Version: "v2.0.0"},
+			{Version: "v1.0.0"},
+			{Version: "not-a-version"},
+		},
+	}
+	versions, err := pr.ListReleases(InstallOptions{Getters: []Getter{getter}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.String())
+	}
+	if diff := cmp.Diff([]string{"1.0.0", "1.2.3", "2.0.0"}, got); diff != "" {
+		t.Fatalf("unexpected releases, constraints should not apply: %s", diff)
+	}
+
+	if _, err := pr.ListReleases(InstallOptions{Getters: []Getter{&mockPluginGetter{}}}); err == nil {
+		t.Fatal("expected an error without any release")
+	}
+}
+
 type mockPluginGetter struct {
 	Releases            []Release
 	ChecksumFileEntries map[string][]ChecksumFileEntry
@@ -24,6 +24,10 @@ type Getter struct {
 	APIMinor   string
 	HttpClient *http.Client
 	Name       string
+	// BaseURL is the root of the site the plugins are fetched from. It
+	// defaults to https://releases.hashicorp.com/, setting it allows
+	// installing plugins from a mirror with the same layout.
+	BaseURL string
 }
 
 var _ plugingetter.Getter = &Getter{}
@@ -82,27 +86,32 @@ func (g *Getter) Get(what string, opts plugingetter.GetOptions) (io.ReadCloser,
 		g.HttpClient = &http.Client{}
 	}
 
+	baseURL := officialReleaseURL
+	if g.BaseURL != "" {
+		baseURL = strings.TrimSuffix(g.BaseURL, "/") + "/"
+	}
+
 	var req *http.Request
 	transform := transformZipStream()
 
 	switch what {
 	case "releases":
 		// https://releases.hashicorp.com/packer-plugin-docker/index.json
-		url := filepath.ToSlash(officialReleaseURL + ghURI.PluginType() + "/index.json")
+		url := filepath.ToSlash(baseURL + ghURI.PluginType() + "/index.json")
 		req, err = http.NewRequest("GET", url, nil)
 		transform = transformReleasesVersionStream
 	case "sha256":
 		// https://releases.hashicorp.com/packer-plugin-docker/8.0.0/packer-plugin-docker_1.1.1_SHA256SUMS
-		url := filepath.ToSlash(officialReleaseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + ghURI.PluginType() + "_" + opts.VersionString() + "_SHA256SUMS")
+		url := filepath.ToSlash(baseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + ghURI.PluginType() + "_" + opts.VersionString() + "_SHA256SUMS")
 		transform = gh.TransformChecksumStream()
 		req, err = http.NewRequest("GET", url, nil)
 	case "meta":
 		// https://releases.hashicorp.com/packer-plugin-docker/8.0.0/packer-plugin-docker_1.1.1_manifest.json
-		url := filepath.ToSlash(officialReleaseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + ghURI.PluginType() + "_" + opts.VersionString() + "_manifest.json")
+		url := filepath.ToSlash(baseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + ghURI.PluginType() + "_" + opts.VersionString() + "_manifest.json")
 		req, err = http.NewRequest("GET", url, nil)
 	case "zip":
 		// https://releases.hashicorp.com/packer-plugin-docker/1.1.1/packer-plugin-docker_1.1.1_darwin_arm64.zip
-		url := filepath.ToSlash(officialReleaseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + opts.ExpectedZipFilename())
+		url := filepath.ToSlash(baseURL + ghURI.PluginType() + "/" + opts.VersionString() + "/" + opts.ExpectedZipFilename())
 		req, err = http.NewRequest("GET", url, nil)
 	default:
 		return nil, fmt.Errorf("%q not implemented", what)
@@ -115,7 +124,7 @@ func (g *Getter) Get(what string, opts plugingetter.GetOptions) (io.ReadCloser,
 
 	resp, err := g.HttpClient.Do(req)
 	if err != nil || resp.StatusCode >= 400 {
-		log.Printf("[ERROR] Got error while trying getting data from releases.hashicorp.com, %v", err)
+		log.Printf("[ERROR] Got error while trying getting data from %s, %v", g.Name, err)
 		return nil, plugingetter.HTTPFailure
 	}
 
@@ -0,0 +1,71 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// MirrorEnvVar is the environment variable used to install plugins from a
+// mirror instead of the internet. It takes precedence over the plugin_mirror
+// setting of the configuration file.
+const MirrorEnvVar = "PACKER_PLUGIN_MIRROR"
+
+// NewNetworkMirror returns a Getter fetching plugins from an HTTP server laid
+// out like releases.hashicorp.com, i.e.:
+//
+//	<url>/packer-plugin-docker/index.json
+//	<url>/packer-plugin-docker/1.1.1/packer-plugin-docker_1.1.1_SHA256SUMS
+//	<url>/packer-plugin-docker/1.1.1/packer-plugin-docker_1.1.1_manifest.json
+//	<url>/packer-plugin-docker/1.1.1/packer-plugin-docker_1.1.1_linux_amd64.zip
+func NewNetworkMirror(url string) *Getter {
+	return &Getter{
+		Name:    url,
+		BaseURL: url,
+	}
+}
+
+// NewFilesystemMirror returns a Getter installing plugins from a local
+// directory with the same layout as a network mirror.
+func NewFilesystemMirror(dir string) *Getter {
+	return &Getter{
+		Name:    dir,
+		BaseURL: "file:///",
+		HttpClient: &http.Client{
+			Transport: http.NewFileTransport(http.Dir(dir)),
+		},
+	}
+}
+
+// NewMirror returns a network mirror Getter when location is an http or
+// https URL, and a filesystem mirror Getter when it is a directory or a
+// file:// URL.
+func NewMirror(location string) (*Getter, error) {
+	u, err := url.Parse(location)
+	if err == nil {
+		switch u.Scheme {
+		case "http", "https":
+			return NewNetworkMirror(location), nil
+		case "file":
+			location = u.Path
+		}
+	}
+
+	dir, err := filepath.Abs(location)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("plugin mirror %q: %s", location, err)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("plugin mirror %q is not a directory", location)
+	}
+	return NewFilesystemMirror(dir), nil
+}
@@ -0,0 +1,97 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package release
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+func newTestMirrorDir(t *testing.T) string {
+	dir := t.TempDir()
+	pluginDir := filepath.Join(dir, "packer-plugin-docker")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	index := `{"name": "packer-plugin-docker", "versions": {"1.0.0": {"name": "packer-plugin-docker", "version": "1.0.0"}}}`
+	if err := os.WriteFile(filepath.Join(pluginDir, "index.json"), []byte(index), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func mirrorReleases(t *testing.T, g *Getter, source string) ([]plugingetter.Release, error) {
+	identifier, err := addrs.ParsePluginSourceString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := g.Get("releases", plugingetter.GetOptions{
+		PluginRequirement: &plugingetter.Requirement{Identifier: identifier},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plugingetter.ParseReleases(rc)
+}
+
+func TestMirror_releases(t *testing.T) {
+	dir := newTestMirrorDir(t)
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	getters := map[string]*Getter{
+		"filesystem": NewFilesystemMirror(dir),
+		"network":    NewNetworkMirror(server.URL),
+	}
+	for name, g := range getters {
+		t.Run(name, func(t *testing.T) {
+			releases, err := mirrorReleases(t, g, "github.com/hashicorp/docker")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff([]plugingetter.Release{{Version: "v1.0.0"}}, releases); diff != "" {
+				t.Fatalf("unexpected releases: %s", diff)
+			}
+
+			_, err = mirrorReleases(t, g, "github.com/hashicorp/amazon")
+			if !errors.Is(err, plugingetter.HTTPFailure) {
+				t.Fatalf("expected an HTTP failure for a plugin missing from the mirror, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewMirror(t *testing.T) {
+	dir := newTestMirrorDir(t)
+
+	tests := []struct {
+		location    string
+		wantBaseURL string
+		wantErr     bool
+	}{
+		{"https://mirror.example.com/packer/", "https://mirror.example.com/packer/", false},
+		{"http://mirror.example.com", "http://mirror.example.com", false},
+		{dir, "file:///", false},
+		{"file://" + filepath.ToSlash(dir), "file:///", false},
+		{filepath.Join(dir, "missing"), "", true},
+		{filepath.Join(dir, "packer-plugin-docker", "index.json"), "", true},
+	}
+	for _, tt := range tests {
+		g, err := NewMirror(tt.location)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("NewMirror(%q) error = %v, wantErr %t", tt.location, err, tt.wantErr)
+		}
+		if err == nil && g.BaseURL != tt.wantBaseURL {
+			t.Errorf("NewMirror(%q).BaseURL = %q, want %q", tt.location, g.BaseURL, tt.wantBaseURL)
+		}
+	}
+}
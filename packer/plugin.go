@@ -30,6 +30,9 @@ type PluginConfig struct {
 	PostProcessors  PostProcessorSet
 	DataSources     DatasourceSet
 	ReleasesOnly    bool
+	// Mirror is the directory or URL of the mirror from which remote plugins
+	// are installed, when set.
+	Mirror string
 	// UseProtobuf is set if all the plugin candidates support protobuf, and
 	// the user has not forced usage of gob for serialisation.
 	UseProtobuf bool
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
+	"regexp"
 	"time"
 
 	hcpSbomProvisioner "github.com/hashicorp/packer/provisioner/hcp-sbom"
@@ -19,6 +21,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/packerbuilderdata"
+	"github.com/hashicorp/packer-plugin-sdk/retry"
 )
 
 // A HookedProvisioner represents a provisioner and information describing it
@@ -26,6 +29,8 @@ type HookedProvisioner struct {
 	Provisioner packersdk.Provisioner
 	Config      interface{}
 	TypeName    string
+	// Name is the user-given name of the provisioner, if any.
+	Name string
 }
 
 // A Hook implementation that runs the given provisioners.
@@ -33,6 +38,8 @@ type ProvisionHook struct {
 	// The provisioners to run as part of the hook. These should already
 	// be prepared (by calling Prepare) at some earlier stage.
 	Provisioners []*HookedProvisioner
+	// Report, when set, records the outcome of each provisioner run.
+	Report *BuildReport
 }
 
 // BuilderDataCommonKeys is the list of common keys that all builder will
@@ -126,10 +133,12 @@ func (h *ProvisionHook) Run(ctx context.Context, name string, ui packersdk.Ui, c
 	}
 	for _, p := range h.Provisioners {
 		ts := CheckpointReporter.AddSpan(p.TypeName, "provisioner", p.Config)
+		step := h.Report.addProvisioner(p.TypeName, p.Name)
 
 		cast := CastDataToMap(data)
 		err := p.Provisioner.Provision(ctx, ui, comm, cast)
 
+		step.end(err)
 		ts.End(err)
 		if err != nil {
 			return err
@@ -142,9 +151,11 @@ func (h *ProvisionHook) Run(ctx context.Context, name string, ui packersdk.Ui, c
 // ProvisionerWrapOptions contains options for wrapping a provisioner with
 // additional behavior like pausing, timeouts, and retries.
 type ProvisionerWrapOptions struct {
-	PauseBefore time.Duration
-	Timeout     time.Duration
-	MaxRetries  int
+	PauseBefore  time.Duration
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff RetryBackoff
+	RetryOn      []*regexp.Regexp
 }
 
 // WrapProvisionerWithOptions wraps a provisioner with additional behavior
@@ -169,6 +180,8 @@ func WrapProvisionerWithOptions(provisioner packersdk.Provisioner, opts Provisio
 	if opts.MaxRetries != 0 {
 		wrapped = &RetriedProvisioner{
 			MaxRetries:  opts.MaxRetries,
+			Backoff:     opts.RetryBackoff,
+			RetryOn:     opts.RetryOn,
 			Provisioner: wrapped,
 		}
 	}
@@ -202,10 +215,50 @@ func (p *PausedProvisioner) Provision(ctx context.Context, ui packersdk.Ui, comm
 	return p.Provisioner.Provision(ctx, ui, comm, generatedData)
 }
 
+// RetryBackoff describes how long a RetriedProvisioner waits between two
+// attempts. The zero value retries immediately.
+type RetryBackoff struct {
+	// Delay before the first retry.
+	InitialDelay time.Duration
+	// Factor, at least 1, applied to the delay after each retry. 1 gives a
+	// constant delay.
+	Multiplier float64
+	// Upper bound for the delay, 0 means unbounded.
+	MaxDelay time.Duration
+	// Fraction of the delay, between 0 and 1, that is randomly added or
+	// removed from each wait so that parallel builds do not retry in lockstep.
+	Jitter float64
+}
+
+// delays returns a function computing the successive waits between retries.
+func (b RetryBackoff) delays() func() time.Duration {
+	backoff := retry.Backoff{
+		InitialBackoff: b.InitialDelay,
+		MaxBackoff:     b.MaxDelay,
+		Multiplier:     b.Multiplier,
+	}
+	return func() time.Duration {
+		wait := backoff.Linear()
+		if b.Jitter > 0 && wait > 0 {
+			delta := float64(wait) * b.Jitter
+			wait += time.Duration(delta * (2*rand.Float64() - 1))
+		}
+		if b.MaxDelay != 0 && wait > b.MaxDelay {
+			wait = b.MaxDelay
+		}
+		return wait
+	}
+}
+
 // RetriedProvisioner is a Provisioner implementation that retries
 // the provisioner whenever there's an error.
 type RetriedProvisioner struct {
-	MaxRetries  int
+	MaxRetries int
+	// Backoff configures the wait between two attempts.
+	Backoff RetryBackoff
+	// RetryOn restricts retries to errors whose message matches at least
+	// one of the expressions. When empty, every error is retried.
+	RetryOn     []*regexp.Regexp
 	Provisioner packersdk.Provisioner
 }
 
@@ -215,6 +268,19 @@ func (r *RetriedProvisioner) Prepare(raws ...interface{}) error {
 	return r.Provisioner.Prepare(raws...)
 }
 
+// retryable tells whether err should trigger a new attempt.
+func (r *RetriedProvisioner) retryable(err error) bool {
+	if len(r.RetryOn) == 0 {
+		return true
+	}
+	for _, re := range r.RetryOn {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *RetriedProvisioner) Provision(ctx context.Context, ui packersdk.Ui, comm packersdk.Communicator, generatedData map[string]interface{}) error {
 	if ctx.Err() != nil { // context was cancelled
 		return ctx.Err()
@@ -225,15 +291,30 @@ func (r *RetriedProvisioner) Provision(ctx context.Context, ui packersdk.Ui, com
 		return nil
 	}
 
+	nextDelay := r.Backoff.delays()
 	leftTries := r.MaxRetries
 	for ; leftTries > 0; leftTries-- {
 		if ctx.Err() != nil { // context was cancelled
 			return ctx.Err()
 		}
 
-		ui.Say(fmt.Sprintf("Provisioner failed with %q, retrying with %d trie(s) left", err, leftTries))
+		if !r.retryable(err) {
+			ui.Say(fmt.Sprintf("Provisioner failed with %q, error is not retryable", err))
+			return err
+		}
+
+		if delay := nextDelay(); delay > 0 {
+			ui.Say(fmt.Sprintf("Provisioner failed with %q, retrying in %s with %d trie(s) left", err, delay, leftTries))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else {
+			ui.Say(fmt.Sprintf("Provisioner failed with %q, retrying with %d trie(s) left", err, leftTries))
+		}
 
-		err := r.Provisioner.Provision(ctx, ui, comm, generatedData)
+		err = r.Provisioner.Provision(ctx, ui, comm, generatedData)
 		if err == nil {
 			return nil
 		}
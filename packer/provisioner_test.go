@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -31,8 +32,8 @@ func TestProvisionHook(t *testing.T) {
 
 	hook := &ProvisionHook{
 		Provisioners: []*HookedProvisioner{
-			{pA, nil, ""},
-			{pB, nil, ""},
+			{pA, nil, "", ""},
+			{pB, nil, "", ""},
 		},
 	}
 
@@ -57,8 +58,8 @@ func TestProvisionHook_nilComm(t *testing.T) {
 
 	hook := &ProvisionHook{
 		Provisioners: []*HookedProvisioner{
-			{pA, nil, ""},
-			{pB, nil, ""},
+			{pA, nil, "", ""},
+			{pB, nil, "", ""},
 		},
 	}
 
@@ -81,7 +82,7 @@ func TestProvisionHook_cancel(t *testing.T) {
 
 	hook := &ProvisionHook{
 		Provisioners: []*HookedProvisioner{
-			{p, nil, ""},
+			{p, nil, "", ""},
 		},
 	}
 
@@ -290,6 +291,82 @@ func TestRetriedProvisionerProvision(t *testing.T) {
 	}
 }
 
+func TestRetriedProvisionerProvision_notRetryable(t *testing.T) {
+	mock := &packersdk.MockProvisioner{
+		ProvFunc: func(ctx context.Context) error {
+			return errors.New("script exited with status 2")
+		},
+	}
+
+	prov := &RetriedProvisioner{
+		MaxRetries:  2,
+		RetryOn:     []*regexp.Regexp{regexp.MustCompile("mirror unavailable")},
+		Provisioner: mock,
+	}
+
+	err := prov.Provision(context.Background(), testUi(), new(packersdk.MockCommunicator), make(map[string]interface{}))
+	if err == nil {
+		t.Fatal("should have errored")
+	}
+	if mock.ProvRetried {
+		t.Fatal("prov should NOT be retried")
+	}
+}
+
+func TestRetriedProvisionerProvision_backoff(t *testing.T) {
+	mock := &packersdk.MockProvisioner{
+		ProvFunc: func(ctx context.Context) error {
+			return errors.New("mirror unavailable")
+		},
+	}
+
+	prov := &RetriedProvisioner{
+		MaxRetries: 2,
+		Backoff: RetryBackoff{
+			InitialDelay: 50 * time.Millisecond,
+			Multiplier:   1,
+		},
+		RetryOn:     []*regexp.Regexp{regexp.MustCompile("mirror unavailable")},
+		Provisioner: mock,
+	}
+
+	start := time.Now()
+	err := prov.Provision(context.Background(), testUi(), new(packersdk.MockCommunicator), make(map[string]interface{}))
+	if err != nil {
+		t.Fatal("should not have errored")
+	}
+	if !mock.ProvRetried {
+		t.Fatal("prov should be retried")
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Fatal("retry should have waited")
+	}
+}
+
+func TestRetryBackoff_delays(t *testing.T) {
+	backoff := RetryBackoff{
+		InitialDelay: time.Second,
+		Multiplier:   2,
+		MaxDelay:     5 * time.Second,
+	}
+	next := backoff.delays()
+	for i, expected := range []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	} {
+		if got := next(); got != expected {
+			t.Fatalf("delay %d: expected %s, got %s", i, expected, got)
+		}
+	}
+
+	backoff.Jitter = 0.5
+	next = backoff.delays()
+	for i := 0; i < 10; i++ {
+		if got := next(); got < 500*time.Millisecond || got > 5*time.Second {
+			t.Fatalf("delay %d out of jitter bounds: %s", i, got)
+		}
+	}
+}
+
 func TestRetriedProvisionerCancelledProvision(t *testing.T) {
 	// Don't retry if context is cancelled
 	ctx, topCtxCancel := context.WithCancel(context.Background())
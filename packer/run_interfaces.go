@@ -46,6 +46,11 @@ type InitializeOptions struct {
 	//
 	// This is optional and defaults to false for now, but this may become a default later.
 	UseSequential bool
+	// EnvMetadata is the metadata gathered from the environment of the build:
+	// operating system, VCS and CI/CD details. It is exposed to templates as
+	// `packer.build_metadata`, and to post-processors in the generated data of
+	// the builds.
+	EnvMetadata map[string]interface{}
 }
 
 type PluginBinaryDetector interface {
@@ -94,6 +99,10 @@ type ConfigFixer interface {
 
 type InspectConfigOptions struct {
 	packersdk.Ui
+
+	// Format is the output format: empty for the human readable output, or
+	// "json".
+	Format string
 }
 
 type ConfigInspector interface {
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 	"time"
 
 	checkpoint "github.com/hashicorp/go-checkpoint"
@@ -41,6 +42,9 @@ type PackerReport struct {
 }
 
 type CheckpointTelemetry struct {
+	// spansLock protects spans, as builds and their post-processors can
+	// run concurrently.
+	spansLock     sync.Mutex
 	spans         []*TelemetrySpan
 	signatureFile string
 	startTime     time.Time
@@ -117,7 +121,9 @@ func (c *CheckpointTelemetry) AddSpan(name, pluginType string, options interface
 		StartTime: time.Now().UTC(),
 		Type:      pluginType,
 	}
+	c.spansLock.Lock()
 	c.spans = append(c.spans, ts)
+	c.spansLock.Unlock()
 	return ts
 }
 
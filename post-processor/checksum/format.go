@@ -0,0 +1,187 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// FormatLegacy writes `<checksum>\t<file>` lines.
+	FormatLegacy = "legacy"
+	// FormatGNU writes `<checksum>  <file>` lines, as sha256sum and other
+	// GNU coreutils do.
+	FormatGNU = "gnu"
+	// FormatBSD writes `<TYPE> (<file>) = <checksum>` lines, as the BSD
+	// tools and `sha256sum --tag` do.
+	FormatBSD = "bsd"
+	// FormatJSON writes a JSON list of entries.
+	FormatJSON = "json"
+)
+
+func validFormat(format string) bool {
+	switch format {
+	case FormatLegacy, FormatGNU, FormatBSD, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// taggedFormat reports whether the format records the checksum type of each
+// entry, allowing several checksum types to share a file.
+func taggedFormat(format string) bool {
+	return format == FormatBSD || format == FormatJSON
+}
+
+// entry is the checksum of a file.
+type entry struct {
+	Name         string `json:"name"`
+	ChecksumType string `json:"checksum_type"`
+	Sum          string `json:"checksum"`
+}
+
+var bsdTags = map[string]string{
+	"md5":      "MD5",
+	"sha1":     "SHA1",
+	"sha224":   "SHA224",
+	"sha256":   "SHA256",
+	"sha384":   "SHA384",
+	"sha512":   "SHA512",
+	"sha3-256": "SHA3-256",
+	"blake2b":  "BLAKE2b",
+	"blake3":   "BLAKE3",
+}
+
+func checksumTypeFromBSDTag(tag string) string {
+	for ct, t := range bsdTags {
+		if t == tag {
+			return ct
+		}
+	}
+	return ""
+}
+
+// writeEntries adds the entries to the checksum file.
+func writeEntries(path, format string, entries []entry) error {
+	if format == FormatJSON {
+		existing, err := readEntries(path, format, "")
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(append(existing, entries...), "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, append(out, '\n'), os.FileMode(0644))
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		switch format {
+		case FormatGNU:
+			fmt.Fprintf(&buf, "%s  %s\n", e.Sum, e.Name)
+		case FormatBSD:
+			fmt.Fprintf(&buf, "%s (%s) = %s\n", bsdTags[e.ChecksumType], e.Name, e.Sum)
+		default:
+			fmt.Fprintf(&buf, "%s\t%s\n", e.Sum, e.Name)
+		}
+	}
+
+	fw, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(buf.Bytes()); err != nil {
+		fw.Close()
+		return err
+	}
+	return fw.Close()
+}
+
+// readEntries parses a checksum file. Formats that do not record the checksum
+// type of each entry use checksumType. A missing file has no entries.
+func readEntries(path, format, checksumType string) ([]entry, error) {
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	if format == FormatJSON {
+		if len(bytes.TrimSpace(contents)) == 0 {
+			return nil, nil
+		}
+		if err := json.Unmarshal(contents, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		e, ok := parseLine(format, line, checksumType)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid %s checksum line %q", lineNumber, format, line)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func parseLine(format, line, checksumType string) (entry, bool) {
+	switch format {
+	case FormatBSD:
+		// TYPE (name) = checksum
+		tag, rest, ok := strings.Cut(line, " (")
+		if !ok {
+			return entry{}, false
+		}
+		idx := strings.LastIndex(rest, ") = ")
+		if idx < 0 {
+			return entry{}, false
+		}
+		ct := checksumTypeFromBSDTag(tag)
+		if ct == "" {
+			return entry{}, false
+		}
+		return entry{Name: rest[:idx], ChecksumType: ct, Sum: rest[idx+len(") = "):]}, true
+	case FormatGNU:
+		// checksum  name, or checksum *name in binary mode
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok || len(name) < 2 {
+			return entry{}, false
+		}
+		return entry{Name: name[1:], ChecksumType: checksumType, Sum: sum}, true
+	default:
+		sum, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			return entry{}, false
+		}
+		return entry{Name: name, ChecksumType: checksumType, Sum: sum}, true
+	}
+}
+
+// lookupEntry returns the last recorded checksum of the file for the checksum
+// type.
+func lookupEntry(entries []entry, checksumType, name string) (string, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Name == name && e.ChecksumType == checksumType {
+			return strings.ToLower(e.Sum), true
+		}
+	}
+	return "", false
+}
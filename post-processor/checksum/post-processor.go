@@ -11,6 +11,7 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
 	"hash"
 	"io"
@@ -22,6 +23,9 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/template/config"
 	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/zeebo/blake3"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 )
 
 type Config struct {
@@ -29,6 +33,8 @@ type Config struct {
 
 	ChecksumTypes []string `mapstructure:"checksum_types"`
 	OutputPath    string   `mapstructure:"output"`
+	Format        string   `mapstructure:"format"`
+	Verify        bool     `mapstructure:"verify"`
 	ctx           interpolate.Context
 }
 
@@ -51,6 +57,12 @@ func getHash(t string) hash.Hash {
 		h = sha512.New384()
 	case "sha512":
 		h = sha512.New()
+	case "sha3-256":
+		h = sha3.New256()
+	case "blake2b":
+		h, _ = blake2b.New512(nil)
+	case "blake3":
+		h = blake3.New()
 	}
 	return h
 }
@@ -82,6 +94,15 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		}
 	}
 
+	if p.config.Format == "" {
+		p.config.Format = FormatLegacy
+	}
+	if !validFormat(p.config.Format) {
+		errs = packersdk.MultiErrorAppend(errs,
+			fmt.Errorf("Unrecognized format: %s, expected one of %s, %s, %s or %s",
+				p.config.Format, FormatLegacy, FormatGNU, FormatBSD, FormatJSON))
+	}
+
 	if p.config.OutputPath == "" {
 		p.config.OutputPath = "packer_{{.BuildName}}_{{.BuilderType}}_{{.ChecksumType}}.checksum"
 	}
@@ -100,7 +121,6 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 
 func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifact packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
 	files := artifact.Files()
-	var h hash.Hash
 
 	var generatedData map[interface{}]interface{}
 	stateData := artifact.State("generated_data")
@@ -116,44 +136,72 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 	generatedData["BuildName"] = p.config.PackerBuildName
 	generatedData["BuilderType"] = p.config.PackerBuilderType
 
-	newartifact := NewArtifact(artifact.Files())
-
+	// Each checksum type can be written to its own file, render the output
+	// path for all of them.
+	checksumFiles := make(map[string]string, len(p.config.ChecksumTypes))
 	for _, ct := range p.config.ChecksumTypes {
-		h = getHash(ct)
 		generatedData["ChecksumType"] = ct
 		p.config.ctx.Data = generatedData
+		checksumFile, err := interpolate.Render(p.config.OutputPath, &p.config.ctx)
+		if err != nil {
+			return nil, false, true, err
+		}
+		checksumFiles[ct] = checksumFile
+	}
 
-		for _, art := range files {
-			checksumFile, err := interpolate.Render(p.config.OutputPath, &p.config.ctx)
-			if err != nil {
-				return nil, false, true, err
+	// Only the bsd and json formats record the checksum type of each line.
+	// The legacy format historically allowed mixing them in one file, which
+	// can be written but not verified.
+	if !taggedFormat(p.config.Format) && (p.config.Format != FormatLegacy || p.config.Verify) {
+		typesByFile := map[string]string{}
+		for _, ct := range p.config.ChecksumTypes {
+			if other, ok := typesByFile[checksumFiles[ct]]; ok && other != ct {
+				return nil, false, true, fmt.Errorf(
+					"the %s and %s checksums are both stored in %s: use the {{.ChecksumType}} variable in output, or the %s or %s format",
+					other, ct, checksumFiles[ct], FormatBSD, FormatJSON)
 			}
+			typesByFile[checksumFiles[ct]] = ct
+		}
+	}
 
-			if _, err := os.Stat(checksumFile); err != nil {
-				newartifact.files = append(newartifact.files, checksumFile)
-			}
-			if err := os.MkdirAll(filepath.Dir(checksumFile), os.FileMode(0755)); err != nil {
-				return nil, false, true, fmt.Errorf("unable to create dir: %s", err.Error())
-			}
-			fw, err := os.OpenFile(checksumFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0644))
-			if err != nil {
-				return nil, false, true, fmt.Errorf("unable to create file %s: %s", checksumFile, err.Error())
-			}
-			fr, err := os.Open(art)
-			if err != nil {
-				fw.Close()
-				return nil, false, true, fmt.Errorf("unable to open file %s: %s", art, err.Error())
-			}
+	newartifact := NewArtifact(artifact.Files())
+
+	if p.config.Verify {
+		if err := p.verify(ui, files, checksumFiles); err != nil {
+			return nil, false, true, err
+		}
+		return newartifact, true, true, nil
+	}
 
-			if _, err = io.Copy(h, fr); err != nil {
-				fr.Close()
-				fw.Close()
-				return nil, false, true, fmt.Errorf("unable to compute %s hash for %s", ct, art)
+	outputs := map[string][]entry{}
+	var outputOrder []string
+	for _, art := range files {
+		sums, err := computeChecksums(art, p.config.ChecksumTypes)
+		if err != nil {
+			return nil, false, true, err
+		}
+		for _, ct := range p.config.ChecksumTypes {
+			checksumFile := checksumFiles[ct]
+			if _, ok := outputs[checksumFile]; !ok {
+				outputOrder = append(outputOrder, checksumFile)
 			}
-			fr.Close()
-			_, _ = fw.WriteString(fmt.Sprintf("%x\t%s\n", h.Sum(nil), filepath.Base(art)))
-			fw.Close()
-			h.Reset()
+			outputs[checksumFile] = append(outputs[checksumFile], entry{
+				ChecksumType: ct,
+				Name:         filepath.Base(art),
+				Sum:          sums[ct],
+			})
+		}
+	}
+
+	for _, checksumFile := range outputOrder {
+		if _, err := os.Stat(checksumFile); err != nil {
+			newartifact.files = append(newartifact.files, checksumFile)
+		}
+		if err := os.MkdirAll(filepath.Dir(checksumFile), os.FileMode(0755)); err != nil {
+			return nil, false, true, fmt.Errorf("unable to create dir: %s", err.Error())
+		}
+		if err := writeEntries(checksumFile, p.config.Format, outputs[checksumFile]); err != nil {
+			return nil, false, true, fmt.Errorf("unable to write file %s: %s", checksumFile, err.Error())
 		}
 	}
 
@@ -161,3 +209,75 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 	// delete the very artifact we're checksumming.
 	return newartifact, true, true, nil
 }
+
+// verify checks the artifact files against the checksums recorded in the
+// checksum files, and fails if any of them is missing or does not match.
+func (p *PostProcessor) verify(ui packersdk.Ui, files []string, checksumFiles map[string]string) error {
+	recorded := map[string][]entry{}
+	errs := new(packersdk.MultiError)
+	for _, art := range files {
+		sums, err := computeChecksums(art, p.config.ChecksumTypes)
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(art)
+		for _, ct := range p.config.ChecksumTypes {
+			checksumFile := checksumFiles[ct]
+			entries, ok := recorded[checksumFile]
+			if !ok {
+				entries, err = readEntries(checksumFile, p.config.Format, ct)
+				if err != nil {
+					return fmt.Errorf("unable to read checksum file %s: %s", checksumFile, err)
+				}
+				recorded[checksumFile] = entries
+			}
+
+			expected, found := lookupEntry(entries, ct, name)
+			switch {
+			case !found:
+				errs = packersdk.MultiErrorAppend(errs,
+					fmt.Errorf("no %s checksum for %s in %s", ct, name, checksumFile))
+			case expected != sums[ct]:
+				errs = packersdk.MultiErrorAppend(errs,
+					fmt.Errorf("%s checksum mismatch for %s: expected %s, got %s", ct, name, expected, sums[ct]))
+			default:
+				ui.Say(fmt.Sprintf("%s: %s checksum OK", name, ct))
+			}
+		}
+	}
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// computeChecksums reads the file once and returns its hex encoded checksum
+// for each of the checksum types.
+func computeChecksums(path string, checksumTypes []string) (map[string]string, error) {
+	hashes := make(map[string]hash.Hash, len(checksumTypes))
+	writers := make([]io.Writer, 0, len(checksumTypes))
+	for _, ct := range checksumTypes {
+		if _, ok := hashes[ct]; ok {
+			continue
+		}
+		h := getHash(ct)
+		hashes[ct] = h
+		writers = append(writers, h)
+	}
+
+	fr, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file %s: %s", path, err.Error())
+	}
+	defer fr.Close()
+
+	if _, err = io.Copy(io.MultiWriter(writers...), fr); err != nil {
+		return nil, fmt.Errorf("unable to compute checksums for %s: %s", path, err.Error())
+	}
+
+	sums := make(map[string]string, len(hashes))
+	for ct, h := range hashes {
+		sums[ct] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
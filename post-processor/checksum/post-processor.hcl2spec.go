@@ -20,6 +20,8 @@ type FlatConfig struct {
 	PackerSensitiveVars []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
 	ChecksumTypes       []string          `mapstructure:"checksum_types" cty:"checksum_types" hcl:"checksum_types"`
 	OutputPath          *string           `mapstructure:"output" cty:"output" hcl:"output"`
+	Format              *string           `mapstructure:"format" cty:"format" hcl:"format"`
+	Verify              *bool             `mapstructure:"verify" cty:"verify" hcl:"verify"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -44,6 +46,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"packer_sensitive_variables": &hcldec.AttrSpec{Name: "packer_sensitive_variables", Type: cty.List(cty.String), Required: false},
 		"checksum_types":             &hcldec.AttrSpec{Name: "checksum_types", Type: cty.List(cty.String), Required: false},
 		"output":                     &hcldec.AttrSpec{Name: "output", Type: cty.String, Required: false},
+		"format":                     &hcldec.AttrSpec{Name: "format", Type: cty.String, Required: false},
+		"verify":                     &hcldec.AttrSpec{Name: "verify", Type: cty.Bool, Required: false},
 	}
 	return s
 }
@@ -43,6 +43,122 @@ func TestChecksumSHA1(t *testing.T) {
 	defer f.Close()
 }
 
+func TestChecksumFormats(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			"gnu",
+			"c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a  package.txt\n",
+		},
+		{
+			"bsd",
+			"SHA256 (package.txt) = c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a\n" +
+				"SHA3-256 (package.txt) = d6ea8f9a1f22e1298e5a9506bd066f23cc56001f5d36582344a628649df53ae8\n" +
+				"BLAKE3 (package.txt) = 793c10bc0b28c378330d39edace7260af9da81d603b8ffede2706a21eda893f4\n",
+		},
+		{
+			"json",
+			`[
+  {
+    "name": "package.txt",
+    "checksum_type": "sha256",
+    "checksum": "c0535e4be2b79ffd93291305436bf889314e4a3faec05ecffcbb7df31ad9e51a"
+  },
+  {
+    "name": "package.txt",
+    "checksum_type": "sha3-256",
+    "checksum": "d6ea8f9a1f22e1298e5a9506bd066f23cc56001f5d36582344a628649df53ae8"
+  },
+  {
+    "name": "package.txt",
+    "checksum_type": "blake3",
+    "checksum": "793c10bc0b28c378330d39edace7260af9da81d603b8ffede2706a21eda893f4"
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			checksumTypes := `["sha256", "sha3-256", "blake3"]`
+			if tt.format == "gnu" {
+				checksumTypes = `["sha256"]`
+			}
+			config := fmt.Sprintf(`{"post-processors": [{"type": "checksum", "checksum_types": %s, "format": %q, "output": "checksums"}]}`,
+				checksumTypes, tt.format)
+			artifact := testChecksum(t, config)
+			defer artifact.Destroy()
+
+			buf, err := os.ReadFile("checksums")
+			if err != nil {
+				t.Fatalf("Unable to read checksum file: %s", err)
+			}
+			if string(buf) != tt.expected {
+				t.Errorf("Unexpected checksum file:\n%s\nexpected:\n%s", buf, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChecksumBLAKE2b(t *testing.T) {
+	const config = `{"post-processors": [{"type": "checksum", "checksum_types": ["blake2b"], "output": "b2sums"}]}`
+	artifact := testChecksum(t, config)
+	defer artifact.Destroy()
+
+	buf, err := os.ReadFile("b2sums")
+	if err != nil {
+		t.Fatalf("Unable to read checksum file: %s", err)
+	}
+	expected := "0389abc5ab1e8e170e95aff19d341ecbf88b83a12dd657291ec1254108ea97352c2ff5116902b9fe4021bfe5a6a4372b0f7c9fc2d7dd810c29f85511d1e04c59\tpackage.txt\n"
+	if string(buf) != expected {
+		t.Errorf("Failed to compute checksum: %s", buf)
+	}
+}
+
+func TestChecksumVerify(t *testing.T) {
+	for _, format := range []string{"legacy", "gnu", "bsd", "json"} {
+		t.Run(format, func(t *testing.T) {
+			output := format + "_{{.ChecksumType}}.checksum"
+			write := fmt.Sprintf(`{"post-processors": [{"type": "checksum", "checksum_types": ["sha256", "sha512"], "format": %q, "output": %q}]}`,
+				format, output)
+			verify := fmt.Sprintf(`{"post-processors": [{"type": "checksum", "checksum_types": ["sha256", "sha512"], "format": %q, "output": %q, "verify": true}]}`,
+				format, output)
+
+			artifact := testChecksum(t, write)
+			defer artifact.Destroy()
+
+			if _, err := runChecksum(t, verify); err != nil {
+				t.Fatalf("Verification of valid checksums failed: %s", err)
+			}
+
+			sha256File := format + "_sha256.checksum"
+			buf, err := os.ReadFile(sha256File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tampered := strings.Replace(string(buf), "c0535e4b", "00000000", 1)
+			if err := os.WriteFile(sha256File, []byte(tampered), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err = runChecksum(t, verify)
+			if err == nil || !strings.Contains(err.Error(), "sha256 checksum mismatch for package.txt") {
+				t.Fatalf("expected a checksum mismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumVerify_missing(t *testing.T) {
+	const config = `{"post-processors": [{"type": "checksum", "checksum_types": ["sha256"], "output": "missing.checksum", "verify": true}]}`
+	_, err := runChecksum(t, config)
+	if err == nil || !strings.Contains(err.Error(), "no sha256 checksum for package.txt") {
+		t.Fatalf("expected a missing checksum error, got %v", err)
+	}
+}
+
 // Test Helpers
 
 func setup(t *testing.T) (packersdk.Ui, packersdk.Artifact, error) {
@@ -78,6 +194,15 @@ func setup(t *testing.T) (packersdk.Ui, packersdk.Artifact, error) {
 }
 
 func testChecksum(t *testing.T, config string) packersdk.Artifact {
+	artifactOut, err := runChecksum(t, config)
+	if err != nil {
+		t.Fatalf("Failed to checksum artifact: %s", err)
+	}
+
+	return artifactOut
+}
+
+func runChecksum(t *testing.T, config string) (packersdk.Artifact, error) {
 	ui, artifact, err := setup(t)
 	if err != nil {
 		t.Fatalf("Error bootstrapping test: %s", err)
@@ -101,9 +226,5 @@ func testChecksum(t *testing.T, config string) packersdk.Artifact {
 	checksum.config.PackerBuilderType = "file"
 
 	artifactOut, _, _, err := checksum.PostProcess(context.Background(), ui, artifact)
-	if err != nil {
-		t.Fatalf("Failed to checksum artifact: %s", err)
-	}
-
-	return artifactOut
+	return artifactOut, err
 }
@@ -23,6 +23,7 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/template/config"
 	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/klauspost/compress/zstd"
 	"github.com/klauspost/pgzip"
 	"github.com/pierrec/lz4/v4"
 	"github.com/ulikunitz/xz"
@@ -197,6 +198,14 @@ func (p *PostProcessor) PostProcess(
 				fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
 		defer output.Close()
+	case "zstd":
+		ui.Say(fmt.Sprintf("Using zstd compression with %d cores for %s",
+			runtime.GOMAXPROCS(-1), target))
+		output, err = makeZstdWriter(outputFile, p.config.CompressionLevel)
+		if err != nil {
+			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
+		}
+		defer output.Close()
 	default:
 		output = outputFile
 	}
@@ -261,6 +270,8 @@ func (config *Config) detectFromFilename() {
 		"bgzf":  "bgzf",
 		"xz":    "xz",
 		"bzip2": "bzip2",
+		"zst":   "zstd",
+		"zstd":  "zstd",
 	}
 
 	if config.Format == "" {
@@ -374,6 +385,26 @@ func makePgzipWriter(output io.WriteCloser, compressionLevel int) (io.WriteClose
 	return gzipWriter, nil
 }
 
+func makeZstdWriter(output io.WriteCloser, compressionLevel int) (io.WriteCloser, error) {
+	// zstd only has four encoder levels, spread them over the 1-9 range used
+	// by the other algorithms.
+	level := zstd.SpeedDefault
+	switch {
+	case compressionLevel < 0:
+	case compressionLevel <= 2:
+		level = zstd.SpeedFastest
+	case compressionLevel <= 5:
+		level = zstd.SpeedDefault
+	case compressionLevel <= 8:
+		level = zstd.SpeedBetterCompression
+	default:
+		level = zstd.SpeedBestCompression
+	}
+	return zstd.NewWriter(output,
+		zstd.WithEncoderLevel(level),
+		zstd.WithEncoderConcurrency(runtime.GOMAXPROCS(-1)))
+}
+
 func createTarArchive(files []string, output io.WriteCloser) error {
 	archive := tar.NewWriter(output)
 	defer archive.Close()
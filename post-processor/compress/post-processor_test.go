@@ -18,6 +18,7 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/template"
 	"github.com/hashicorp/packer/builder/file"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
 )
 
@@ -61,6 +62,16 @@ func TestDetectFilename(t *testing.T) {
 	if lotsOfDots.Algorithm != "lz4" {
 		t.Error("Expected to find lz4 algorithm setting")
 	}
+
+	// Test .tar.zst
+	tarZst := Config{OutputPath: "test.tar.zst"}
+	tarZst.detectFromFilename()
+	if tarZst.Archive != "tar" {
+		t.Error("Expected to find tar archive setting")
+	}
+	if tarZst.Algorithm != "zstd" {
+		t.Error("Expected to find zstd algorithm setting")
+	}
 }
 
 const expectedFileContents = "Hello world!"
@@ -236,6 +247,27 @@ func TestArchive(t *testing.T) {
 			lz4Reader := lz4.NewReader(archive)
 			return io.ReadAll(lz4Reader)
 		},
+		"zst": func(archive *os.File) ([]byte, error) {
+			zstdReader, err := zstd.NewReader(archive)
+			if err != nil {
+				return nil, err
+			}
+			defer zstdReader.Close()
+			return io.ReadAll(zstdReader)
+		},
+		"tar.zst": func(archive *os.File) ([]byte, error) {
+			zstdReader, err := zstd.NewReader(archive)
+			if err != nil {
+				return nil, err
+			}
+			defer zstdReader.Close()
+			tarReader := tar.NewReader(zstdReader)
+			_, err = tarReader.Next()
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(tarReader)
+		},
 	}
 
 	tmpArchiveFile := "temp-archive-package"
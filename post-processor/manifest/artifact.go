@@ -8,18 +8,25 @@ import "fmt"
 const BuilderId = "packer.post-processor.manifest"
 
 type ArtifactFile struct {
-	Name string `json:"name"`
-	Size int64  `json:"size"`
+	Name string `json:"name" yaml:"name"`
+	Size int64  `json:"size" yaml:"size"`
+	// Checksums maps a checksum type, i.e. sha256, to the hex encoded
+	// checksum of the file.
+	Checksums map[string]string `json:"checksums,omitempty" yaml:"checksums,omitempty"`
 }
 
 type Artifact struct {
-	BuildName     string            `json:"name"`
-	BuilderType   string            `json:"builder_type"`
-	BuildTime     int64             `json:"build_time,omitempty"`
-	ArtifactFiles []ArtifactFile    `json:"files"`
-	ArtifactId    string            `json:"artifact_id"`
-	PackerRunUUID string            `json:"packer_run_uuid"`
-	CustomData    map[string]string `json:"custom_data"`
+	BuildName      string                 `json:"name" yaml:"name"`
+	BuilderType    string                 `json:"builder_type" yaml:"builder_type"`
+	BuildTime      int64                  `json:"build_time,omitempty" yaml:"build_time,omitempty"`
+	ArtifactFiles  []ArtifactFile         `json:"files" yaml:"files"`
+	ArtifactId     string                 `json:"artifact_id" yaml:"artifact_id"`
+	PackerRunUUID  string                 `json:"packer_run_uuid" yaml:"packer_run_uuid"`
+	CustomData     map[string]string      `json:"custom_data" yaml:"custom_data"`
+	GeneratedData  map[string]interface{} `json:"generated_data,omitempty" yaml:"generated_data,omitempty"`
+	Variables      map[string]string      `json:"variables,omitempty" yaml:"variables,omitempty"`
+	PackerVersion  string                 `json:"packer_version,omitempty" yaml:"packer_version,omitempty"`
+	PluginVersions map[string]string      `json:"plugin_versions,omitempty" yaml:"plugin_versions,omitempty"`
 }
 
 func (a *Artifact) BuilderId() string {
@@ -0,0 +1,96 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported manifest formats.
+const (
+	FormatJSON      = "json"
+	FormatYAML      = "yaml"
+	FormatJSONLines = "jsonl"
+)
+
+// formatFromPath guesses the format of a manifest from its extension,
+// defaulting to JSON.
+func formatFromPath(path string) string {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".jsonl":
+		return FormatJSONLines
+	default:
+		return FormatJSON
+	}
+}
+
+// decodeManifest parses the content of a manifest file. In the JSON Lines
+// format every line is a build, and the last run UUID is the one of the last
+// build.
+func decodeManifest(format string, contents []byte) (*ManifestFile, error) {
+	manifestFile := &ManifestFile{}
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return manifestFile, nil
+	}
+
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(contents, manifestFile); err != nil {
+			return nil, err
+		}
+	case FormatJSONLines:
+		scanner := bufio.NewScanner(bytes.NewReader(contents))
+		scanner.Buffer(nil, len(contents)+1)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var artifact Artifact
+			if err := json.Unmarshal(line, &artifact); err != nil {
+				return nil, err
+			}
+			manifestFile.Builds = append(manifestFile.Builds, artifact)
+			manifestFile.LastRunUUID = artifact.PackerRunUUID
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.Unmarshal(contents, manifestFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifestFile, nil
+}
+
+// encodeManifest serialises a manifest file in the given format.
+func encodeManifest(format string, manifestFile *ManifestFile) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(manifestFile)
+	case FormatJSONLines:
+		out := &bytes.Buffer{}
+		enc := json.NewEncoder(out)
+		for _, artifact := range manifestFile.Builds {
+			if err := enc.Encode(artifact); err != nil {
+				return nil, err
+			}
+		}
+		return out.Bytes(), nil
+	case FormatJSON:
+		return json.MarshalIndent(manifestFile, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q", format)
+	}
+}
@@ -8,13 +8,20 @@ package manifest
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"hash"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -37,7 +44,49 @@ type Config struct {
 	// engine](/packer/docs/templates/legacy_json_templates/engine). Therefore, you
 	// may use user variables and template functions in this field.
 	CustomData map[string]string `mapstructure:"custom_data"`
-	ctx        interpolate.Context
+	// Checksums to compute for every artifact file, among `sha256` and
+	// `sha512`. They are written in the `checksums` field of each file. No
+	// checksum is computed by default.
+	ChecksumTypes []string `mapstructure:"checksum_types"`
+	// Write the `generated_data` of the artifact, for example the
+	// `SourceAMI` of an Amazon build, to the `generated_data` field. Values
+	// of keys containing `password` and values of sensitive variables are
+	// replaced by `<sensitive>`. This defaults to false.
+	IncludeGeneratedData bool `mapstructure:"include_generated_data"`
+	// Write the input variables of the template to the `variables` field.
+	// Values of sensitive variables are replaced by `<sensitive>`. This
+	// defaults to false.
+	IncludeVariables bool `mapstructure:"include_variables"`
+	// Write the version of Packer and of the plugins used by the build to
+	// the `packer_version` and `plugin_versions` fields. This defaults to
+	// false.
+	IncludePluginVersions bool `mapstructure:"include_plugin_versions"`
+	// The format of the manifest: `json`, `yaml`, or `jsonl` for JSON Lines,
+	// where every line is a build. This defaults to `yaml` when `output`
+	// ends with `.yaml` or `.yml`, `jsonl` when it ends with `.jsonl`, and
+	// `json` otherwise.
+	Format string `mapstructure:"format"`
+	// How long to wait for other builds to release their lock on the
+	// manifest file before failing, for example `30s` or `5m`. This defaults
+	// to `1m`.
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+	// The strings of the sensitive variables of an HCL2 template, set by
+	// Packer to redact them from the generated data.
+	PackerSensitiveValues []string `mapstructure:"packer_sensitive_values" undocumented:"true"`
+	ctx                   interpolate.Context
+}
+
+const (
+	defaultLockTimeout = time.Minute
+	lockRetryDelay     = 100 * time.Millisecond
+)
+
+// sensitiveValue replaces sensitive data in the manifest.
+const sensitiveValue = "<sensitive>"
+
+var checksumHashes = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
 }
 
 type PostProcessor struct {
@@ -45,8 +94,8 @@ type PostProcessor struct {
 }
 
 type ManifestFile struct {
-	Builds      []Artifact `json:"builds"`
-	LastRunUUID string     `json:"last_run_uuid"`
+	Builds      []Artifact `json:"builds" yaml:"builds"`
+	LastRunUUID string     `json:"last_run_uuid" yaml:"last_run_uuid"`
 }
 
 func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
@@ -64,14 +113,36 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		return err
 	}
 
+	switch p.config.Format {
+	case "":
+		p.config.Format = formatFromPath(p.config.OutputPath)
+	case FormatJSON, FormatYAML, FormatJSONLines:
+	default:
+		return fmt.Errorf("Invalid format %q, expected one of %q, %q or %q",
+			p.config.Format, FormatJSON, FormatYAML, FormatJSONLines)
+	}
+
 	if p.config.OutputPath == "" {
-		p.config.OutputPath = "packer-manifest.json"
+		p.config.OutputPath = "packer-manifest." + p.config.Format
 	}
 
 	if err = interpolate.Validate(p.config.OutputPath, &p.config.ctx); err != nil {
 		return fmt.Errorf("Error parsing target template: %s", err)
 	}
 
+	if p.config.LockTimeout == 0 {
+		p.config.LockTimeout = defaultLockTimeout
+	}
+	if p.config.LockTimeout < 0 {
+		return fmt.Errorf("lock_timeout must be positive, got %s", p.config.LockTimeout)
+	}
+
+	for _, checksumType := range p.config.ChecksumTypes {
+		if _, ok := checksumHashes[checksumType]; !ok {
+			return fmt.Errorf("Unsupported checksum type %q, expected sha256 or sha512", checksumType)
+		}
+	}
+
 	return nil
 }
 
@@ -107,6 +178,11 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 		} else {
 			af.Name = name
 		}
+		if len(p.config.ChecksumTypes) > 0 {
+			if af.Checksums, err = fileChecksums(name, p.config.ChecksumTypes); err != nil {
+				return source, true, true, err
+			}
+		}
 		artifact.ArtifactFiles = append(artifact.ArtifactFiles, af)
 	}
 	artifact.ArtifactId = source.Id()
@@ -117,6 +193,20 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	if p.config.StripTime {
 		artifact.BuildTime = 0
 	}
+	if p.config.IncludeGeneratedData {
+		if data, ok := stringKeys(generatedData).(map[string]interface{}); ok {
+			artifact.GeneratedData = p.redactGeneratedData(data)
+		}
+	}
+	if p.config.IncludeVariables {
+		artifact.Variables = p.redactedVariables()
+	}
+	if p.config.IncludePluginVersions {
+		artifact.PackerVersion = p.config.PackerCoreVersion
+		if versions, ok := source.State("packer_plugin_versions").(map[string]string); ok {
+			artifact.PluginVersions = versions
+		}
+	}
 	// Since each post-processor runs in a different process we need a way to
 	// coordinate between various post-processors in a single packer run. We do
 	// this by setting a UUID per run and tracking this in the manifest file.
@@ -126,19 +216,22 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	// the file before we proceed.
 	artifact.PackerRunUUID = os.Getenv("PACKER_RUN_UUID")
 
-	// Create a lock file with exclusive access. If this fails we will retry
-	// after a delay.
-	lockFilename := p.config.OutputPath + ".lock"
-	for i := 0; i < 3; i++ {
-		// The file should not be locked for very long so we'll keep this short.
-		time.Sleep((time.Duration(i) * 200 * time.Millisecond))
-		_, err = os.OpenFile(lockFilename, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
-		if err == nil {
-			break
+	// Builds running in parallel may write to the same manifest, so hold an
+	// exclusive lock on a companion lock file while the manifest is read,
+	// updated and written back. The lock file is left in place: removing it
+	// while another process waits on it would let two processes hold a lock
+	// at the same time.
+	lock := flock.New(p.config.OutputPath + ".lock")
+	lockCtx, cancel := context.WithTimeout(ctx, p.config.LockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(lockCtx, lockRetryDelay)
+	if !locked {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", p.config.LockTimeout)
 		}
-		log.Printf("Error locking manifest file for reading and writing. Will sleep and retry. %s", err)
+		return source, true, true, fmt.Errorf("Unable to lock %s: %s", p.config.OutputPath, err)
 	}
-	defer os.Remove(lockFilename)
+	defer lock.Unlock()
 
 	// Read the current manifest file from disk
 	var contents []byte
@@ -146,12 +239,10 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 		return source, true, true, fmt.Errorf("Unable to open %s for reading: %s", p.config.OutputPath, err)
 	}
 
-	// Parse the manifest file JSON, if we have one
-	manifestFile := &ManifestFile{}
-	if len(contents) > 0 {
-		if err = json.Unmarshal(contents, manifestFile); err != nil {
-			return source, true, true, fmt.Errorf("Unable to parse content from %s: %s", p.config.OutputPath, err)
-		}
+	// Parse the manifest file, if we have one
+	manifestFile, err := decodeManifest(p.config.Format, contents)
+	if err != nil {
+		return source, true, true, fmt.Errorf("Unable to parse content from %s: %s", p.config.OutputPath, err)
 	}
 
 	// If -force is set and we are not on same run, truncate the file. Otherwise
@@ -164,13 +255,13 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	manifestFile.Builds = append(manifestFile.Builds, *artifact)
 	manifestFile.LastRunUUID = os.Getenv("PACKER_RUN_UUID")
 
-	// Write JSON to disk
-	if out, err := json.MarshalIndent(manifestFile, "", "  "); err == nil {
-		if err = os.WriteFile(p.config.OutputPath, out, 0664); err != nil {
-			return source, true, true, fmt.Errorf("Unable to write %s: %s", p.config.OutputPath, err)
-		}
-	} else {
-		return source, true, true, fmt.Errorf("Unable to marshal JSON %s", err)
+	// Write the manifest to disk
+	out, err := encodeManifest(p.config.Format, manifestFile)
+	if err != nil {
+		return source, true, true, fmt.Errorf("Unable to marshal %s %s", p.config.Format, err)
+	}
+	if err = writeFileAtomic(p.config.OutputPath, out, 0664); err != nil {
+		return source, true, true, fmt.Errorf("Unable to write %s: %s", p.config.OutputPath, err)
 	}
 
 	// The manifest should never delete the artifacts it is set to record, so it
@@ -178,6 +269,32 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	return source, true, true, nil
 }
 
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so that readers never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 func createInterpolatedCustomData(config *Config, customData string) (string, error) {
 	interpolatedCmd, err := interpolate.Render(customData, &config.ctx)
 	if err != nil {
@@ -185,3 +302,134 @@ func createInterpolatedCustomData(config *Config, customData string) (string, er
 	}
 	return interpolatedCmd, nil
 }
+
+// fileChecksums returns the hex encoded checksums of a file for each of the
+// given checksum types.
+func fileChecksums(path string, checksumTypes []string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open %s for checksumming: %s", path, err)
+	}
+	defer f.Close()
+
+	hashes := make(map[string]hash.Hash, len(checksumTypes))
+	writers := make([]io.Writer, 0, len(checksumTypes))
+	for _, checksumType := range checksumTypes {
+		h := checksumHashes[checksumType]()
+		hashes[checksumType] = h
+		writers = append(writers, h)
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, fmt.Errorf("Unable to checksum %s: %s", path, err)
+	}
+
+	checksums := make(map[string]string, len(hashes))
+	for checksumType, h := range hashes {
+		checksums[checksumType] = hex.EncodeToString(h.Sum(nil))
+	}
+	return checksums, nil
+}
+
+// redactedVariables returns the user variables, with the values of sensitive
+// ones redacted.
+func (p *PostProcessor) redactedVariables() map[string]string {
+	sensitive := map[string]bool{}
+	for _, name := range p.config.PackerSensitiveVars {
+		sensitive[name] = true
+	}
+
+	vars := make(map[string]string, len(p.config.PackerUserVars)+len(sensitive))
+	for name, value := range p.config.PackerUserVars {
+		if sensitive[name] {
+			value = sensitiveValue
+		}
+		vars[name] = value
+	}
+	for name := range sensitive {
+		vars[name] = sensitiveValue
+	}
+	return vars
+}
+
+// redactGeneratedData returns a copy of the generated data of the artifact,
+// without passwords nor values of sensitive variables, at any depth.
+func (p *PostProcessor) redactGeneratedData(data map[string]interface{}) map[string]interface{} {
+	secrets := append([]string(nil), p.config.PackerSensitiveValues...)
+	// Legacy JSON templates pass the values of sensitive variables with the
+	// other user variables.
+	for _, name := range p.config.PackerSensitiveVars {
+		if value := p.config.PackerUserVars[name]; value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	// Replace longer secrets first, in case one contains another.
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	return redact(data, secrets).(map[string]interface{})
+}
+
+// redact replaces the secrets found in the strings of v, and the values of
+// keys containing `password`, by sensitiveValue.
+func redact(v interface{}, secrets []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if strings.Contains(strings.ToLower(key), "password") {
+				m[key] = sensitiveValue
+				continue
+			}
+			m[key] = redact(value, secrets)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = redact(value, secrets)
+		}
+		return s
+	case []string:
+		s := make([]string, len(v))
+		for i, value := range v {
+			s[i] = redact(value, secrets).(string)
+		}
+		return s
+	case string:
+		for _, secret := range secrets {
+			if secret != "" {
+				v = strings.ReplaceAll(v, secret, sensitiveValue)
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// stringKeys converts the map[interface{}]interface{} maps of generated data,
+// as decoded from plugins, to map[string]interface{} maps that can be
+// marshalled.
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = stringKeys(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = stringKeys(value)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, value := range v {
+			s[i] = stringKeys(value)
+		}
+		return s
+	default:
+		return v
+	}
+}
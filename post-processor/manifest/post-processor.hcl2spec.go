@@ -10,18 +10,25 @@ import (
 // FlatConfig is an auto-generated flat version of Config.
 // Where the contents of a field with a `mapstructure:,squash` tag are bubbled up.
 type FlatConfig struct {
-	PackerBuildName     *string           `mapstructure:"packer_build_name" cty:"packer_build_name" hcl:"packer_build_name"`
-	PackerBuilderType   *string           `mapstructure:"packer_builder_type" cty:"packer_builder_type" hcl:"packer_builder_type"`
-	PackerCoreVersion   *string           `mapstructure:"packer_core_version" cty:"packer_core_version" hcl:"packer_core_version"`
-	PackerDebug         *bool             `mapstructure:"packer_debug" cty:"packer_debug" hcl:"packer_debug"`
-	PackerForce         *bool             `mapstructure:"packer_force" cty:"packer_force" hcl:"packer_force"`
-	PackerOnError       *string           `mapstructure:"packer_on_error" cty:"packer_on_error" hcl:"packer_on_error"`
-	PackerUserVars      map[string]string `mapstructure:"packer_user_variables" cty:"packer_user_variables" hcl:"packer_user_variables"`
-	PackerSensitiveVars []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
-	OutputPath          *string           `mapstructure:"output" cty:"output" hcl:"output"`
-	StripPath           *bool             `mapstructure:"strip_path" cty:"strip_path" hcl:"strip_path"`
-	StripTime           *bool             `mapstructure:"strip_time" cty:"strip_time" hcl:"strip_time"`
-	CustomData          map[string]string `mapstructure:"custom_data" cty:"custom_data" hcl:"custom_data"`
+	PackerBuildName       *string           `mapstructure:"packer_build_name" cty:"packer_build_name" hcl:"packer_build_name"`
+	PackerBuilderType     *string           `mapstructure:"packer_builder_type" cty:"packer_builder_type" hcl:"packer_builder_type"`
+	PackerCoreVersion     *string           `mapstructure:"packer_core_version" cty:"packer_core_version" hcl:"packer_core_version"`
+	PackerDebug           *bool             `mapstructure:"packer_debug" cty:"packer_debug" hcl:"packer_debug"`
+	PackerForce           *bool             `mapstructure:"packer_force" cty:"packer_force" hcl:"packer_force"`
+	PackerOnError         *string           `mapstructure:"packer_on_error" cty:"packer_on_error" hcl:"packer_on_error"`
+	PackerUserVars        map[string]string `mapstructure:"packer_user_variables" cty:"packer_user_variables" hcl:"packer_user_variables"`
+	PackerSensitiveVars   []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
+	OutputPath            *string           `mapstructure:"output" cty:"output" hcl:"output"`
+	StripPath             *bool             `mapstructure:"strip_path" cty:"strip_path" hcl:"strip_path"`
+	StripTime             *bool             `mapstructure:"strip_time" cty:"strip_time" hcl:"strip_time"`
+	CustomData            map[string]string `mapstructure:"custom_data" cty:"custom_data" hcl:"custom_data"`
+	ChecksumTypes         []string          `mapstructure:"checksum_types" cty:"checksum_types" hcl:"checksum_types"`
+	IncludeGeneratedData  *bool             `mapstructure:"include_generated_data" cty:"include_generated_data" hcl:"include_generated_data"`
+	IncludeVariables      *bool             `mapstructure:"include_variables" cty:"include_variables" hcl:"include_variables"`
+	IncludePluginVersions *bool             `mapstructure:"include_plugin_versions" cty:"include_plugin_versions" hcl:"include_plugin_versions"`
+	Format                *string           `mapstructure:"format" cty:"format" hcl:"format"`
+	LockTimeout           *string           `mapstructure:"lock_timeout" cty:"lock_timeout" hcl:"lock_timeout"`
+	PackerSensitiveValues []string          `mapstructure:"packer_sensitive_values" undocumented:"true" cty:"packer_sensitive_values" hcl:"packer_sensitive_values"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -48,6 +55,13 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"strip_path":                 &hcldec.AttrSpec{Name: "strip_path", Type: cty.Bool, Required: false},
 		"strip_time":                 &hcldec.AttrSpec{Name: "strip_time", Type: cty.Bool, Required: false},
 		"custom_data":                &hcldec.AttrSpec{Name: "custom_data", Type: cty.Map(cty.String), Required: false},
+		"checksum_types":             &hcldec.AttrSpec{Name: "checksum_types", Type: cty.List(cty.String), Required: false},
+		"include_generated_data":     &hcldec.AttrSpec{Name: "include_generated_data", Type: cty.Bool, Required: false},
+		"include_variables":          &hcldec.AttrSpec{Name: "include_variables", Type: cty.Bool, Required: false},
+		"include_plugin_versions":    &hcldec.AttrSpec{Name: "include_plugin_versions", Type: cty.Bool, Required: false},
+		"format":                     &hcldec.AttrSpec{Name: "format", Type: cty.String, Required: false},
+		"lock_timeout":               &hcldec.AttrSpec{Name: "lock_timeout", Type: cty.String, Required: false},
+		"packer_sensitive_values":    &hcldec.AttrSpec{Name: "packer_sensitive_values", Type: cty.List(cty.String), Required: false},
 	}
 	return s
 }
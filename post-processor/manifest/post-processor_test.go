@@ -0,0 +1,243 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofrs/flock"
+	"github.com/google/go-cmp/cmp"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+func testArtifact(t *testing.T) *packersdk.MockArtifact {
+	file := filepath.Join(t.TempDir(), "image.raw")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &packersdk.MockArtifact{
+		IdValue:    "image",
+		FilesValue: []string{file},
+		StateValues: map[string]interface{}{
+			"generated_data": map[string]interface{}{
+				"SourceAMI":     "ami-1234",
+				"WinRMPassword": "hunter2",
+				"Token":         "s3cr3t",
+			},
+			"packer_plugin_versions": map[string]string{"amazon": "1.2.3"},
+		},
+	}
+}
+
+func runManifest(t *testing.T, config map[string]interface{}, artifact packersdk.Artifact) {
+	var p PostProcessor
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %s", err)
+	}
+	if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact); err != nil {
+		t.Fatalf("PostProcess failed: %s", err)
+	}
+}
+
+func TestPostProcessor_content(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "manifest.json")
+	runManifest(t, map[string]interface{}{
+		"output":                     output,
+		"strip_path":                 true,
+		"strip_time":                 true,
+		"checksum_types":             []string{"sha256"},
+		"include_generated_data":     true,
+		"include_variables":          true,
+		"include_plugin_versions":    true,
+		"packer_build_name":          "example",
+		"packer_builder_type":        "null",
+		"packer_core_version":        "1.15.0",
+		"packer_user_variables":      map[string]string{"region": "eu-west-1", "token": "s3cr3t"},
+		"packer_sensitive_variables": []string{"token", "password"},
+	}, testArtifact(t))
+
+	contents, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestFile, err := decodeManifest(FormatJSON, contents)
+	if err != nil {
+		t.Fatalf("invalid manifest: %s", err)
+	}
+
+	expected := []Artifact{{
+		BuildName:   "example",
+		BuilderType: "null",
+		ArtifactFiles: []ArtifactFile{{
+			Name: "image.raw",
+			Size: 5,
+			Checksums: map[string]string{
+				"sha256": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			},
+		}},
+		ArtifactId: "image",
+		GeneratedData: map[string]interface{}{
+			"SourceAMI":     "ami-1234",
+			"WinRMPassword": "<sensitive>",
+			"Token":         "<sensitive>",
+		},
+		Variables: map[string]string{
+			"region":   "eu-west-1",
+			"token":    "<sensitive>",
+			"password": "<sensitive>",
+		},
+		PackerVersion:  "1.15.0",
+		PluginVersions: map[string]string{"amazon": "1.2.3"},
+	}}
+	if diff := cmp.Diff(expected, manifestFile.Builds); diff != "" {
+		t.Fatalf("unexpected manifest content: %s", diff)
+	}
+}
+
+func TestPostProcessor_pluginGeneratedData(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "manifest.json")
+	artifact := &packersdk.MockArtifact{
+		IdValue: "image",
+		StateValues: map[string]interface{}{
+			// Generated data decoded from a plugin has interface{} keys.
+			"generated_data": map[interface{}]interface{}{
+				"SourceAMI": "ami-1234",
+				"Tags":      map[interface{}]interface{}{"os": "ubuntu"},
+			},
+		},
+	}
+	runManifest(t, map[string]interface{}{
+		"output":                 output,
+		"include_generated_data": true,
+	}, artifact)
+
+	contents, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestFile, err := decodeManifest(FormatJSON, contents)
+	if err != nil {
+		t.Fatalf("invalid manifest: %s", err)
+	}
+	expected := map[string]interface{}{
+		"SourceAMI": "ami-1234",
+		"Tags":      map[string]interface{}{"os": "ubuntu"},
+	}
+	if diff := cmp.Diff(expected, manifestFile.Builds[0].GeneratedData); diff != "" {
+		t.Fatalf("unexpected generated data: %s", diff)
+	}
+}
+
+func TestPostProcessor_formats(t *testing.T) {
+	for _, ext := range []string{"json", "yaml", "jsonl"} {
+		t.Run(ext, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "manifest."+ext)
+			config := map[string]interface{}{"output": output}
+
+			runManifest(t, config, testArtifact(t))
+			runManifest(t, config, testArtifact(t))
+
+			contents, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			manifestFile, err := decodeManifest(formatFromPath(output), contents)
+			if err != nil {
+				t.Fatalf("invalid %s manifest: %s\n%s", ext, err, contents)
+			}
+			if len(manifestFile.Builds) != 2 {
+				t.Fatalf("expected 2 builds, got %d:\n%s", len(manifestFile.Builds), contents)
+			}
+			if manifestFile.Builds[1].ArtifactId != "image" {
+				t.Fatalf("unexpected build: %#v", manifestFile.Builds[1])
+			}
+		})
+	}
+}
+
+func TestPostProcessor_invalidConfig(t *testing.T) {
+	for name, config := range map[string]map[string]interface{}{
+		"format":   {"format": "xml"},
+		"checksum": {"checksum_types": []string{"md5"}},
+	} {
+		var p PostProcessor
+		if err := p.Configure(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPostProcessor_concurrentWrites(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "manifest.json")
+
+	artifact := testArtifact(t)
+	const builds = 20
+	var wg sync.WaitGroup
+	for i := 0; i < builds; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var p PostProcessor
+			if err := p.Configure(map[string]interface{}{"output": output}); err != nil {
+				t.Errorf("Configure failed: %s", err)
+				return
+			}
+			if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact); err != nil {
+				t.Errorf("PostProcess failed: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	contents, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifestFile, err := decodeManifest(FormatJSON, contents)
+	if err != nil {
+		t.Fatalf("invalid manifest: %s", err)
+	}
+	if len(manifestFile.Builds) != builds {
+		t.Fatalf("expected %d builds, got %d", builds, len(manifestFile.Builds))
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if diff := cmp.Diff([]string{"manifest.json", "manifest.json.lock"}, names); diff != "" {
+		t.Fatalf("expected only the manifest and its lock file: %s", diff)
+	}
+}
+
+func TestPostProcessor_lockTimeout(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "manifest.json")
+
+	lock := flock.New(output + ".lock")
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	var p PostProcessor
+	if err := p.Configure(map[string]interface{}{"output": output, "lock_timeout": "200ms"}); err != nil {
+		t.Fatalf("Configure failed: %s", err)
+	}
+	_, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), testArtifact(t))
+	if err == nil || !strings.Contains(err.Error(), "Unable to lock") {
+		t.Fatalf("expected a lock error, got %v", err)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Fatalf("manifest should not have been written, stat returned %v", err)
+	}
+}
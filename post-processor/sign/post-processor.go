@@ -0,0 +1,143 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config
+//go:generate packer-sdc struct-markdown
+
+package sign
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+)
+
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeOpenPGP = "openpgp"
+)
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	// The type of the signing key, `ed25519` or `openpgp`. Ed25519 keys
+	// produce minisign signatures written to `<file>.minisig`, OpenPGP keys
+	// produce ASCII armored signatures written to `<file>.asc`.
+	KeyType string `mapstructure:"key_type" required:"true"`
+	// Path to the private key. Ed25519 keys can be minisign secret keys, as
+	// created by `minisign -G`, or PEM encoded keys in the PKCS#8 or OpenSSH
+	// format. OpenPGP keys can be armored or binary, as exported by `gpg
+	// --export-secret-keys`; the first key of the file is used.
+	KeyFile string `mapstructure:"key_file" required:"true"`
+	// The password of the private key, when it is encrypted.
+	KeyPassword string `mapstructure:"key_password"`
+	// The trusted comment of minisign signatures, which is signed along with
+	// the file. This defaults to the signing time and the file name.
+	TrustedComment string `mapstructure:"trusted_comment"`
+
+	ctx interpolate.Context
+}
+
+type PostProcessor struct {
+	config Config
+}
+
+func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	err := config.Decode(&p.config, &config.DecodeOpts{
+		PluginType:         "packer.post-processor.sign",
+		Interpolate:        true,
+		InterpolateContext: &p.config.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{},
+		},
+	}, raws...)
+	if err != nil {
+		return err
+	}
+	errs := new(packersdk.MultiError)
+
+	switch p.config.KeyType {
+	case KeyTypeEd25519, KeyTypeOpenPGP:
+	case "":
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("key_type must be specified"))
+	default:
+		errs = packersdk.MultiErrorAppend(errs,
+			fmt.Errorf("Unrecognized key_type: %s, expected %s or %s", p.config.KeyType, KeyTypeEd25519, KeyTypeOpenPGP))
+	}
+
+	if p.config.KeyFile == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("key_file must be specified"))
+	}
+
+	if p.config.TrustedComment != "" && p.config.KeyType != KeyTypeEd25519 {
+		errs = packersdk.MultiErrorAppend(errs,
+			fmt.Errorf("trusted_comment can only be set with the %s key_type", KeyTypeEd25519))
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (p *PostProcessor) newSigner() (signer, error) {
+	password := []byte(p.config.KeyPassword)
+	if p.config.KeyType == KeyTypeOpenPGP {
+		return newOpenPGPSigner(p.config.KeyFile, password)
+	}
+	s, err := newMinisignSigner(p.config.KeyFile, password)
+	if err != nil {
+		return nil, err
+	}
+	s.trustedComment = p.config.TrustedComment
+	return s, nil
+}
+
+func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	s, err := p.newSigner()
+	if err != nil {
+		return nil, false, true, fmt.Errorf("Unable to load the %s key %s: %s", p.config.KeyType, p.config.KeyFile, err)
+	}
+
+	// Pass the generated data of the signed artifact along, with the key
+	// used, so that downstream post-processors like manifest can record it.
+	generatedData := map[interface{}]interface{}{}
+	switch data := source.State("generated_data").(type) {
+	case map[interface{}]interface{}:
+		for k, v := range data {
+			generatedData[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range data {
+			generatedData[k] = v
+		}
+	}
+	generatedData["SigningKeyType"] = p.config.KeyType
+	generatedData["SigningKeyFingerprint"] = s.Fingerprint()
+	if ms, ok := s.(*minisignSigner); ok {
+		generatedData["SigningPublicKey"] = ms.PublicKey()
+	}
+
+	artifact := &Artifact{generatedData: generatedData}
+	for _, file := range source.Files() {
+		ui.Message(fmt.Sprintf("Signing %s", file))
+		sigPath, err := s.Sign(file)
+		if err != nil {
+			artifact.Destroy()
+			return nil, false, true, fmt.Errorf("Unable to sign %s: %s", file, err)
+		}
+		artifact.files = append(artifact.files, sigPath)
+	}
+
+	// Keep the input artifact: the signatures are worthless without the
+	// files they sign.
+	return artifact, true, true, nil
+}
@@ -0,0 +1,260 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package sign
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
+)
+
+const testContent = "Hello world!"
+
+func testArtifact(t *testing.T) *packersdk.MockArtifact {
+	file := filepath.Join(t.TempDir(), "image.raw")
+	if err := os.WriteFile(file, []byte(testContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &packersdk.MockArtifact{
+		FilesValue: []string{file},
+		StateValues: map[string]interface{}{
+			"generated_data": map[string]interface{}{"SourceAMI": "ami-1234"},
+		},
+	}
+}
+
+func testSign(t *testing.T, config map[string]interface{}, source packersdk.Artifact) packersdk.Artifact {
+	var p PostProcessor
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %s", err)
+	}
+	artifact, keep, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), source)
+	if err != nil {
+		t.Fatalf("PostProcess failed: %s", err)
+	}
+	if !keep {
+		t.Fatal("the input artifact should be kept")
+	}
+	return artifact
+}
+
+// verifyMinisign checks a minisign signature the way `minisign -V` does.
+func verifyMinisign(t *testing.T, sigPath string, pub ed25519.PublicKey, content string) {
+	contents, err := os.ReadFile(sigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(contents), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "untrusted comment: ") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		t.Fatalf("malformed minisign signature:\n%s", contents)
+	}
+	sigBlob, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sigBlob) != 74 || string(sigBlob[:2]) != "ED" {
+		t.Fatalf("malformed signature line %q: %v", lines[1], err)
+	}
+	hash := blake2b.Sum512([]byte(content))
+	if !ed25519.Verify(pub, hash[:], sigBlob[10:]) {
+		t.Fatal("invalid file signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(pub, append(sigBlob[10:], trustedComment...), globalSig) {
+		t.Fatal("invalid trusted comment signature")
+	}
+}
+
+func TestPostProcessor_ed25519PEM(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := testArtifact(t)
+	artifact := testSign(t, map[string]interface{}{
+		"key_type": "ed25519",
+		"key_file": keyFile,
+	}, source)
+
+	files := artifact.Files()
+	if len(files) != 1 || files[0] != source.FilesValue[0]+".minisig" {
+		t.Fatalf("unexpected signature files: %v", files)
+	}
+	verifyMinisign(t, files[0], pub, testContent)
+
+	generatedData := artifact.State("generated_data").(map[interface{}]interface{})
+	if generatedData["SourceAMI"] != "ami-1234" {
+		t.Errorf("generated data of the input artifact was not kept: %v", generatedData)
+	}
+	if generatedData["SigningKeyType"] != "ed25519" || len(generatedData["SigningKeyFingerprint"].(string)) != 16 {
+		t.Errorf("unexpected key in generated data: %v", generatedData)
+	}
+
+	if err := artifact.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(source.FilesValue[0]); err != nil {
+		t.Fatalf("destroying the signatures removed the signed file: %s", err)
+	}
+}
+
+// writeMinisignKey writes a minisign secret key encrypted with cheap scrypt
+// parameters.
+func writeMinisignKey(t *testing.T, priv ed25519.PrivateKey, keyID []byte, password string) string {
+	const opsLimit, memLimit = 65536, 16777216
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+
+	checksum := blake2b.Sum256(append(append([]byte("Ed"), keyID...), priv...))
+	keynum := append(append(append([]byte{}, keyID...), priv...), checksum[:]...)
+	n, r, p := scryptParams(opsLimit, memLimit)
+	stream, err := scrypt.Key([]byte(password), salt, n, r, p, len(keynum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range keynum {
+		keynum[i] ^= stream[i]
+	}
+
+	b := []byte("EdScB2")
+	b = append(b, salt...)
+	b = binary.LittleEndian.AppendUint64(b, opsLimit)
+	b = binary.LittleEndian.AppendUint64(b, memLimit)
+	b = append(b, keynum...)
+
+	keyFile := filepath.Join(t.TempDir(), "minisign.key")
+	contents := "untrusted comment: minisign encrypted secret key\n" + base64.StdEncoding.EncodeToString(b) + "\n"
+	if err := os.WriteFile(keyFile, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return keyFile
+}
+
+func TestPostProcessor_minisignKey(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	keyFile := writeMinisignKey(t, priv, keyID, "correct horse")
+
+	artifact := testSign(t, map[string]interface{}{
+		"key_type":        "ed25519",
+		"key_file":        keyFile,
+		"key_password":    "correct horse",
+		"trusted_comment": "release 1.0",
+	}, testArtifact(t))
+	defer artifact.Destroy()
+
+	verifyMinisign(t, artifact.Files()[0], pub, testContent)
+
+	generatedData := artifact.State("generated_data").(map[interface{}]interface{})
+	if generatedData["SigningKeyFingerprint"] != "0807060504030201" {
+		t.Errorf("unexpected key ID: %v", generatedData["SigningKeyFingerprint"])
+	}
+	expectedPublicKey := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...))
+	if generatedData["SigningPublicKey"] != expectedPublicKey {
+		t.Errorf("unexpected public key: %v", generatedData["SigningPublicKey"])
+	}
+
+	var p PostProcessor
+	if err := p.Configure(map[string]interface{}{
+		"key_type":     "ed25519",
+		"key_file":     keyFile,
+		"key_password": "wrong",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), testArtifact(t)); err == nil || !strings.Contains(err.Error(), "wrong password") {
+		t.Fatalf("expected a wrong password error, got %v", err)
+	}
+}
+
+func TestPostProcessor_openpgp(t *testing.T) {
+	entity, err := openpgp.NewEntity("Packer", "", "packer@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	keyFile := filepath.Join(t.TempDir(), "key.asc")
+	if err := os.WriteFile(keyFile, key.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := testArtifact(t)
+	artifact := testSign(t, map[string]interface{}{
+		"key_type": "openpgp",
+		"key_file": keyFile,
+	}, source)
+	defer artifact.Destroy()
+
+	files := artifact.Files()
+	if len(files) != 1 || files[0] != source.FilesValue[0]+".asc" {
+		t.Fatalf("unexpected signature files: %v", files)
+	}
+	signature, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer signature.Close()
+	if _, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, strings.NewReader(testContent), signature, nil); err != nil {
+		t.Fatalf("invalid signature: %s", err)
+	}
+
+	generatedData := artifact.State("generated_data").(map[interface{}]interface{})
+	if generatedData["SigningKeyFingerprint"] != strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint)) {
+		t.Errorf("unexpected fingerprint: %v", generatedData["SigningKeyFingerprint"])
+	}
+}
+
+func TestPostProcessor_Configure(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing key_type":        {"key_file": "key"},
+		"unknown key_type":        {"key_type": "rsa", "key_file": "key"},
+		"missing key_file":        {"key_type": "ed25519"},
+		"openpgp trusted_comment": {"key_type": "openpgp", "key_file": "key", "trusted_comment": "x"},
+	}
+	for name, config := range tests {
+		var p PostProcessor
+		if err := p.Configure(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
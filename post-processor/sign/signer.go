@@ -0,0 +1,281 @@
+// Copyright IBM Corp. 2024, 2026
+// SPDX-License-Identifier: BUSL-1.1
+
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+// signer writes a detached signature of a file.
+type signer interface {
+	// Sign signs the file and returns the path of the signature.
+	Sign(path string) (string, error)
+	// Fingerprint identifies the public key that verifies the signatures.
+	Fingerprint() string
+}
+
+const (
+	minisignExtension = ".minisig"
+	openpgpExtension  = ".asc"
+)
+
+// minisignSigner writes signatures in the minisign format, which can be
+// checked with `minisign -V` using the matching public key.
+type minisignSigner struct {
+	keyID          [8]byte
+	key            ed25519.PrivateKey
+	trustedComment string
+}
+
+// newMinisignSigner reads an Ed25519 private key, either a minisign secret key
+// or a PEM encoded key in the PKCS#8 or OpenSSH format.
+func newMinisignSigner(keyFile string, password []byte) (*minisignSigner, error) {
+	contents, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(contents, []byte("untrusted comment:")) {
+		return parseMinisignSecretKey(contents, password)
+	}
+
+	var raw interface{}
+	if len(password) > 0 {
+		raw, err = ssh.ParseRawPrivateKeyWithPassphrase(contents, password)
+	} else {
+		raw, err = ssh.ParseRawPrivateKey(contents)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var key ed25519.PrivateKey
+	switch k := raw.(type) {
+	case ed25519.PrivateKey:
+		key = k
+	case *ed25519.PrivateKey:
+		key = *k
+	default:
+		return nil, fmt.Errorf("expected an ed25519 private key, got %T", raw)
+	}
+
+	// Unlike minisign keys, PEM keys carry no key ID, derive one from the
+	// public key so that it is stable across runs.
+	s := &minisignSigner{key: key}
+	sum := blake2b.Sum256(key.Public().(ed25519.PublicKey))
+	copy(s.keyID[:], sum[:8])
+	return s, nil
+}
+
+// parseMinisignSecretKey decodes a minisign secret key file, decrypting it
+// with the password when the key is encrypted.
+func parseMinisignSecretKey(contents []byte, password []byte) (*minisignSigner, error) {
+	lines := strings.Split(strings.ReplaceAll(string(contents), "\r\n", "\n"), "\n")
+	if len(lines) < 2 {
+		return nil, errors.New("invalid minisign secret key")
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid minisign secret key: %s", err)
+	}
+	// signature algorithm, kdf algorithm, checksum algorithm, kdf salt, kdf
+	// opslimit, kdf memlimit, then the key ID, secret key and checksum.
+	if len(b) != 2+2+2+32+8+8+8+64+32 {
+		return nil, errors.New("invalid minisign secret key length")
+	}
+	sigAlg, kdfAlg, cksumAlg := b[0:2], b[2:4], b[4:6]
+	if string(sigAlg) != "Ed" || string(cksumAlg) != "B2" {
+		return nil, errors.New("unsupported minisign secret key algorithm")
+	}
+	salt := b[6:38]
+	opsLimit := binary.LittleEndian.Uint64(b[38:46])
+	memLimit := binary.LittleEndian.Uint64(b[46:54])
+	keynum := append([]byte{}, b[54:]...)
+
+	switch string(kdfAlg) {
+	case "\x00\x00":
+	case "Sc":
+		if len(password) == 0 {
+			return nil, errors.New("the minisign secret key is encrypted, set key_password")
+		}
+		n, r, p := scryptParams(opsLimit, memLimit)
+		stream, err := scrypt.Key(password, salt, n, r, p, len(keynum))
+		if err != nil {
+			return nil, err
+		}
+		for i := range keynum {
+			keynum[i] ^= stream[i]
+		}
+	default:
+		return nil, errors.New("unsupported minisign key derivation algorithm")
+	}
+
+	s := &minisignSigner{key: ed25519.PrivateKey(keynum[8:72])}
+	copy(s.keyID[:], keynum[:8])
+
+	checksum := blake2b.Sum256(append(append(append([]byte{}, sigAlg...), keynum[:8]...), keynum[8:72]...))
+	if !bytes.Equal(checksum[:], keynum[72:]) {
+		return nil, errors.New("wrong password for the minisign secret key")
+	}
+	return s, nil
+}
+
+// scryptParams converts the libsodium limits stored in minisign keys to
+// scrypt parameters.
+func scryptParams(opsLimit, memLimit uint64) (n, r, p int) {
+	if opsLimit < 32768 {
+		opsLimit = 32768
+	}
+	r = 8
+	var nLog2 uint
+	var maxN uint64
+	if opsLimit < memLimit/32 {
+		p = 1
+		maxN = opsLimit / uint64(r*4)
+	} else {
+		maxN = memLimit / uint64(r*128)
+	}
+	for nLog2 = 1; nLog2 < 63; nLog2++ {
+		if uint64(1)<<nLog2 > maxN/2 {
+			break
+		}
+	}
+	if opsLimit >= memLimit/32 {
+		maxRP := (opsLimit / 4) / (uint64(1) << nLog2)
+		if maxRP > 0x3fffffff {
+			maxRP = 0x3fffffff
+		}
+		p = int(maxRP) / r
+	}
+	return 1 << nLog2, r, p
+}
+
+// Fingerprint returns the key ID as printed by minisign.
+func (s *minisignSigner) Fingerprint() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(s.keyID[:]))
+}
+
+// PublicKey returns the public key in the minisign format.
+func (s *minisignSigner) PublicKey() string {
+	b := append([]byte("Ed"), s.keyID[:]...)
+	b = append(b, s.key.Public().(ed25519.PublicKey)...)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func (s *minisignSigner) Sign(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Sign the BLAKE2b-512 hash of the file, like `minisign -S` does by
+	// default, so that large files need not fit in memory.
+	h, _ := blake2b.New512(nil)
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	signature := ed25519.Sign(s.key, h.Sum(nil))
+
+	trustedComment := s.trustedComment
+	if trustedComment == "" {
+		trustedComment = fmt.Sprintf("timestamp:%d\tfile:%s\thashed", time.Now().Unix(), filepath.Base(path))
+	}
+	globalSignature := ed25519.Sign(s.key, append(append([]byte{}, signature...), trustedComment...))
+
+	sigBlob := append([]byte("ED"), s.keyID[:]...)
+	sigBlob = append(sigBlob, signature...)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "untrusted comment: signature from packer secret key\n")
+	fmt.Fprintf(&out, "%s\n", base64.StdEncoding.EncodeToString(sigBlob))
+	fmt.Fprintf(&out, "trusted comment: %s\n", trustedComment)
+	fmt.Fprintf(&out, "%s\n", base64.StdEncoding.EncodeToString(globalSignature))
+
+	sigPath := path + minisignExtension
+	if err := os.WriteFile(sigPath, out.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return sigPath, nil
+}
+
+// openpgpSigner writes ASCII armored OpenPGP signatures, which can be checked
+// with `gpg --verify`.
+type openpgpSigner struct {
+	entity *openpgp.Entity
+}
+
+// newOpenPGPSigner reads the first key of an armored or binary OpenPGP
+// private key ring.
+func newOpenPGPSigner(keyFile string, password []byte) (*openpgpSigner, error) {
+	contents, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities openpgp.EntityList
+	if bytes.Contains(contents, []byte("-----BEGIN PGP")) {
+		entities, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(contents))
+	} else {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(contents))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, errors.New("no OpenPGP private key found")
+	}
+	entity := entities[0]
+
+	if entity.PrivateKey.Encrypted {
+		if len(password) == 0 {
+			return nil, errors.New("the OpenPGP private key is encrypted, set key_password")
+		}
+		if err := entity.DecryptPrivateKeys(password); err != nil {
+			return nil, err
+		}
+	}
+	return &openpgpSigner{entity: entity}, nil
+}
+
+// Fingerprint returns the fingerprint of the primary key.
+func (s *openpgpSigner) Fingerprint() string {
+	return strings.ToUpper(hex.EncodeToString(s.entity.PrimaryKey.Fingerprint))
+}
+
+func (s *openpgpSigner) Sign(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var out bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&out, s.entity, f, &packet.Config{}); err != nil {
+		return "", err
+	}
+
+	sigPath := path + openpgpExtension
+	if err := os.WriteFile(sigPath, out.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return sigPath, nil
+}